@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
@@ -20,6 +21,7 @@ var (
 	ErrAddressToAccountMappingFailure = errors.New("cannot retrieve a valid account for a given address")
 	ErrAccountToKeyMappingFailure     = errors.New("cannot retrieve a valid key for a given account")
 	ErrNoAccountSelected              = errors.New("no account has been selected, please login")
+	ErrAccountNotFound                = errors.New("account is not found in the keystore")
 	ErrInvalidMasterKeyCreated        = errors.New("can not create master extended key")
 )
 
@@ -33,12 +35,14 @@ type GethServiceProvider interface {
 type Manager struct {
 	geth            GethServiceProvider
 	selectedAccount *SelectedExtKey // account that was processed during the last call to SelectAccount()
+	sessions        *sessions       // accounts unlocked with UnlockSession()
 }
 
 // NewManager returns new node account manager.
 func NewManager(geth GethServiceProvider) *Manager {
 	return &Manager{
-		geth: geth,
+		geth:     geth,
+		sessions: newSessions(),
 	}
 }
 
@@ -203,6 +207,21 @@ func (m *Manager) VerifyAccountPassword(keyStoreDir, address, password string) (
 	return key, nil
 }
 
+// VerifyPassword checks that the password decrypts the key of the account in the node's keystore.
+// Unlike SelectAccount, it doesn't change the selected account or unlock anything.
+// keystore.ErrDecrypt is returned if the password is wrong.
+func (m *Manager) VerifyPassword(address, password string) error {
+	_, key, err := m.AddressToDecryptedAccount(address, password)
+	if err == keystore.ErrNoMatch {
+		return ErrAccountNotFound
+	}
+	if err != nil {
+		return err
+	}
+	zeroKey(key)
+	return nil
+}
+
 // SelectAccount selects current account, by verifying that address has corresponding account which can be decrypted
 // using provided password. Once verification is done, all previous identities are removed).
 func (m *Manager) SelectAccount(address, password string) error {
@@ -217,6 +236,9 @@ func (m *Manager) SelectAccount(address, password string) error {
 	}
 
 	account, accountKey, err := keyStore.AccountDecryptedKey(account, password)
+	if err == keystore.ErrNoMatch {
+		return ErrAccountNotFound
+	}
 	if err != nil {
 		return fmt.Errorf("%s: %v", ErrAccountToKeyMappingFailure.Error(), err)
 	}
@@ -246,10 +268,61 @@ func (m *Manager) SelectedAccount() (*SelectedExtKey, error) {
 // Logout clears selectedAccount.
 func (m *Manager) Logout() error {
 	m.selectedAccount = nil
+	m.sessions.endAll(SessionLoggedOut)
+
+	return nil
+}
 
+// UnlockSession decrypts the account key and keeps it in memory for the duration,
+// so that transactions of the account can be completed without a password.
+func (m *Manager) UnlockSession(address, password string, duration time.Duration) error {
+	_, key, err := m.AddressToDecryptedAccount(address, password)
+	if err != nil {
+		return err
+	}
+	m.sessions.add(key, duration)
 	return nil
 }
 
+// SessionAccount returns the account if its session is unlocked. The key of the
+// returned account is a copy, which the caller zeroes with Zero after use.
+func (m *Manager) SessionAccount(address gethcommon.Address) (*SelectedExtKey, bool) {
+	key, ok := m.sessions.get(address)
+	if !ok {
+		return nil, false
+	}
+	return &SelectedExtKey{
+		Address:    address,
+		AccountKey: key,
+	}, true
+}
+
+// SessionAccounts returns addresses of accounts with unlocked sessions.
+func (m *Manager) SessionAccounts() []gethcommon.Address {
+	return m.sessions.addresses()
+}
+
+// HasSession returns true if the account has an unlocked session.
+func (m *Manager) HasSession(address gethcommon.Address) bool {
+	return m.sessions.has(address)
+}
+
+// HasKey returns true if the key of the address is in the keystore.
+func (m *Manager) HasKey(address gethcommon.Address) bool {
+	keyStore, err := m.geth.AccountKeyStore()
+	if err != nil {
+		return false
+	}
+	return keyStore.HasAddress(address)
+}
+
+// OnSessionEnd sets a handler which is called when a session expires or ends on logout.
+func (m *Manager) OnSessionEnd(handler func(address gethcommon.Address, reason string)) {
+	m.sessions.mu.Lock()
+	m.sessions.onEnd = handler
+	m.sessions.mu.Unlock()
+}
+
 // importExtendedKey processes incoming extended key, extracts required info and creates corresponding account key.
 // Once account key is formed, that key is put (if not already) into keystore i.e. key is *encoded* into key file.
 func (m *Manager) importExtendedKey(extKey *extkeys.ExtendedKey, password string) (address, pubKey string, err error) {
@@ -314,6 +387,30 @@ func (m *Manager) Accounts() ([]gethcommon.Address, error) {
 	return filtered, nil
 }
 
+// KeyStoreAccounts returns all accounts present in the keystore, including
+// imported and derived ones, and marks the currently selected account and
+// accounts with an unlocked session.
+// It isn't named Accounts as that method already serves eth_accounts and
+// exposes only the selected account and its sub-accounts to dapps.
+func (m *Manager) KeyStoreAccounts() ([]KeyStoreAccount, error) {
+	keyStore, err := m.geth.AccountKeyStore()
+	if err != nil {
+		return nil, err
+	}
+
+	cachedAccounts := keyStore.Accounts()
+	keyStoreAccounts := make([]KeyStoreAccount, 0, len(cachedAccounts))
+	for _, cachedAccount := range cachedAccounts {
+		keyStoreAccounts = append(keyStoreAccounts, KeyStoreAccount{
+			Address:  cachedAccount.Address,
+			Selected: m.selectedAccount != nil && m.selectedAccount.Address == cachedAccount.Address,
+			Unlocked: m.HasSession(cachedAccount.Address),
+		})
+	}
+
+	return keyStoreAccounts, nil
+}
+
 // refreshSelectedAccount re-populates list of sub-accounts of the currently selected account (if any)
 func (m *Manager) refreshSelectedAccount() {
 	if m.selectedAccount == nil {
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
@@ -246,6 +247,13 @@ func (s *ManagerTestSuite) TestSelectAccount() {
 			"wrong-password",
 			errors.New("cannot retrieve a valid key for a given account: could not decrypt key with given passphrase"),
 		},
+		{
+			"fail_accountNotFound",
+			[]interface{}{s.keyStore, nil},
+			"0x0000000000000000000000000000000000000001",
+			s.password,
+			ErrAccountNotFound,
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -333,6 +341,57 @@ func (s *ManagerTestSuite) TestLogout() {
 	s.Nil(s.accManager.selectedAccount)
 }
 
+func (s *ManagerTestSuite) TestVerifyPassword() {
+	s.gethServiceProvider.EXPECT().AccountKeyStore().Return(s.keyStore, nil).AnyTimes()
+	selectedAccount := s.accManager.selectedAccount
+
+	s.NoError(s.accManager.VerifyPassword(s.address, s.password))
+	s.Equal(keystore.ErrDecrypt, s.accManager.VerifyPassword(s.address, "wrong-password"))
+	s.Equal(ErrAccountNotFound, s.accManager.VerifyPassword("0x79791d3e8f2daa1f7fec29649d152c0ada3cc535", s.password))
+	// selected account is not changed
+	s.Equal(selectedAccount, s.accManager.selectedAccount)
+}
+
+func (s *ManagerTestSuite) TestUnlockSession() {
+	s.gethServiceProvider.EXPECT().AccountKeyStore().Return(s.keyStore, nil).AnyTimes()
+	address := gethcommon.HexToAddress(s.address)
+
+	ended := make(chan string, 1)
+	s.accManager.OnSessionEnd(func(addr gethcommon.Address, reason string) {
+		s.Equal(address, addr)
+		ended <- reason
+	})
+	defer s.accManager.OnSessionEnd(nil)
+
+	s.Error(s.accManager.UnlockSession(s.address, "wrong-password", time.Minute))
+	_, ok := s.accManager.SessionAccount(address)
+	s.False(ok)
+
+	// session ends on logout
+	s.NoError(s.accManager.UnlockSession(s.address, s.password, time.Minute))
+	acc, ok := s.accManager.SessionAccount(address)
+	s.True(ok)
+	s.Equal(address, acc.Address)
+	s.NotNil(acc.AccountKey.PrivateKey)
+	s.NoError(s.accManager.Logout())
+	s.Equal(SessionLoggedOut, <-ended)
+	_, ok = s.accManager.SessionAccount(address)
+	s.False(ok)
+	// a copy returned earlier is still usable
+	s.NotZero(acc.AccountKey.PrivateKey.D.Sign())
+
+	// session expires
+	s.NoError(s.accManager.UnlockSession(s.address, s.password, 10*time.Millisecond))
+	select {
+	case reason := <-ended:
+		s.Equal(SessionExpired, reason)
+	case <-time.After(time.Second):
+		s.Fail("session did not expire")
+	}
+	_, ok = s.accManager.SessionAccount(address)
+	s.False(ok)
+}
+
 // TestAccounts tests cases for (*Manager).Accounts.
 func (s *ManagerTestSuite) TestAccounts() {
 	// Select the test account
@@ -359,6 +418,49 @@ func (s *ManagerTestSuite) TestAccounts() {
 	s.NotNil(accs)
 }
 
+func (s *ManagerTestSuite) TestKeyStoreAccounts() {
+	s.accManager.selectedAccount = nil
+	s.gethServiceProvider.EXPECT().AccountKeyStore().Return(s.keyStore, nil).AnyTimes()
+	accs, err := s.accManager.KeyStoreAccounts()
+	s.NoError(err)
+	s.NotEmpty(accs)
+	for _, acc := range accs {
+		s.False(acc.Selected)
+		s.False(acc.Unlocked)
+	}
+
+	// Select the test account
+	s.NoError(s.accManager.SelectAccount(s.address, s.password))
+	accs, err = s.accManager.KeyStoreAccounts()
+	s.NoError(err)
+	var selected []gethcommon.Address
+	for _, acc := range accs {
+		if acc.Selected {
+			selected = append(selected, acc.Address)
+		}
+	}
+	s.Equal([]gethcommon.Address{gethcommon.HexToAddress(s.address)}, selected)
+
+	// Unlock a session of the test account
+	s.NoError(s.accManager.UnlockSession(s.address, s.password, time.Minute))
+	defer s.accManager.Logout() // nolint: errcheck
+	accs, err = s.accManager.KeyStoreAccounts()
+	s.NoError(err)
+	var unlocked []gethcommon.Address
+	for _, acc := range accs {
+		if acc.Unlocked {
+			unlocked = append(unlocked, acc.Address)
+		}
+	}
+	s.Equal([]gethcommon.Address{gethcommon.HexToAddress(s.address)}, unlocked)
+
+	// Can't get a key store
+	s.reinitMock()
+	s.gethServiceProvider.EXPECT().AccountKeyStore().Return(nil, errKeyStore)
+	_, err = s.accManager.KeyStoreAccounts()
+	s.Equal(errKeyStore, err)
+}
+
 func (s *ManagerTestSuite) TestAddressToDecryptedAccount() {
 	testCases := []struct {
 		name                  string
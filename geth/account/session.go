@@ -0,0 +1,173 @@
+package account
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+const (
+	// EventSessionEnded is triggered when an unlocked session of an account ends
+	EventSessionEnded = "account.session.ended"
+
+	// SessionExpired is a reason of a session which reached its duration
+	SessionExpired = "expired"
+
+	// SessionLoggedOut is a reason of a session which ended on logout
+	SessionLoggedOut = "logged_out"
+)
+
+// SessionEndedEvent is a signal sent when an unlocked session ends
+type SessionEndedEvent struct {
+	Address gethcommon.Address `json:"address"`
+	Reason  string             `json:"reason"`
+}
+
+// NotifyOnSessionEnd sends a notification that an unlocked session ended
+func NotifyOnSessionEnd(address gethcommon.Address, reason string) {
+	signal.Send(signal.Envelope{
+		Type: EventSessionEnded,
+		Event: SessionEndedEvent{
+			Address: address,
+			Reason:  reason,
+		},
+	})
+}
+
+// session is a decrypted key cached for a limited time.
+type session struct {
+	key   *keystore.Key
+	timer *time.Timer
+}
+
+// sessions keeps decrypted keys of unlocked accounts, so that their
+// transactions can be completed without a password.
+type sessions struct {
+	mu      sync.Mutex
+	entries map[gethcommon.Address]*session
+	onEnd   func(address gethcommon.Address, reason string)
+}
+
+func newSessions() *sessions {
+	return &sessions{
+		entries: make(map[gethcommon.Address]*session),
+	}
+}
+
+// add caches the key for the duration, replacing a previous session of the account.
+func (s *sessions) add(key *keystore.Key, duration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if prev, ok := s.entries[key.Address]; ok {
+		prev.timer.Stop()
+		zeroKey(prev.key)
+	}
+	entry := &session{key: key}
+	entry.timer = time.AfterFunc(duration, func() {
+		s.endSession(key.Address, entry, SessionExpired)
+	})
+	s.entries[key.Address] = entry
+}
+
+// get returns a copy of the cached key, so that it stays valid
+// even if the session ends while the key is in use. The caller
+// zeroes the copy once it is no longer needed.
+func (s *sessions) get(address gethcommon.Address) (*keystore.Key, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[address]
+	if !ok {
+		return nil, false
+	}
+	return copyKey(entry.key), true
+}
+
+// has returns true if the account has an unlocked session.
+func (s *sessions) has(address gethcommon.Address) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.entries[address]
+	return ok
+}
+
+// end removes the session of the account and zeroes its key.
+func (s *sessions) end(address gethcommon.Address, reason string) {
+	s.endSession(address, nil, reason)
+}
+
+// endSession ends the session of the account like end does. If expected
+// is not nil, the session is ended only if it is still the current one,
+// so that a timer of a replaced session doesn't end its successor.
+func (s *sessions) endSession(address gethcommon.Address, expected *session, reason string) {
+	s.mu.Lock()
+	entry, ok := s.entries[address]
+	ok = ok && (expected == nil || entry == expected)
+	if ok {
+		entry.timer.Stop()
+		zeroKey(entry.key)
+		delete(s.entries, address)
+	}
+	onEnd := s.onEnd
+	s.mu.Unlock()
+
+	if ok && onEnd != nil {
+		onEnd(address, reason)
+	}
+}
+
+// addresses returns accounts with unlocked sessions.
+func (s *sessions) addresses() []gethcommon.Address {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	addresses := make([]gethcommon.Address, 0, len(s.entries))
+	for address := range s.entries {
+		addresses = append(addresses, address)
+	}
+	return addresses
+}
+
+// endAll ends sessions of all accounts.
+func (s *sessions) endAll(reason string) {
+	for _, address := range s.addresses() {
+		s.end(address, reason)
+	}
+}
+
+// copyKey copies the private key only, the extended key
+// isn't needed to sign transactions.
+func copyKey(key *keystore.Key) *keystore.Key {
+	cp := *key
+	cp.ExtendedKey = nil
+	if key.PrivateKey != nil {
+		cp.PrivateKey = &ecdsa.PrivateKey{
+			PublicKey: key.PrivateKey.PublicKey,
+			D:         new(big.Int).Set(key.PrivateKey.D),
+		}
+	}
+	return &cp
+}
+
+// zeroKey overwrites private key material in memory.
+func zeroKey(key *keystore.Key) {
+	if key.PrivateKey != nil {
+		bits := key.PrivateKey.D.Bits()
+		for i := range bits {
+			bits[i] = 0
+		}
+		key.PrivateKey.D.SetInt64(0)
+	}
+	if key.ExtendedKey != nil {
+		for i := range key.ExtendedKey.KeyData {
+			key.ExtendedKey.KeyData[i] = 0
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package account
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionKeyIsZeroedOnEnd(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	key := &keystore.Key{
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}
+
+	s := newSessions()
+	s.add(key, time.Minute)
+	cached, ok := s.get(key.Address)
+	require.True(t, ok)
+	require.Equal(t, privateKey.D, cached.PrivateKey.D)
+
+	s.end(key.Address, SessionExpired)
+	require.Zero(t, privateKey.D.Sign())
+	_, ok = s.get(key.Address)
+	require.False(t, ok)
+}
+
+func TestReplacedSessionTimerKeepsSuccessor(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	address := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	s := newSessions()
+	s.add(&keystore.Key{Address: address, PrivateKey: privateKey}, time.Minute)
+	replaced := s.entries[address]
+	s.add(copyKey(replaced.key), time.Minute)
+
+	// timer of the replaced session fired before it was stopped
+	s.endSession(address, replaced, SessionExpired)
+	require.True(t, s.has(address))
+
+	cached, ok := s.get(address)
+	require.True(t, ok)
+	acc := &SelectedExtKey{Address: address, AccountKey: cached}
+	acc.Zero()
+	require.Zero(t, cached.PrivateKey.D.Sign())
+	// zeroing the copy doesn't affect the session
+	cached, ok = s.get(address)
+	require.True(t, ok)
+	require.NotZero(t, cached.PrivateKey.D.Sign())
+}
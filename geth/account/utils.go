@@ -20,6 +20,21 @@ type SelectedExtKey struct {
 	SubAccounts []accounts.Account
 }
 
+// KeyStoreAccount describes an account present in the keystore.
+type KeyStoreAccount struct {
+	Address  common.Address `json:"address"`
+	Selected bool           `json:"selected"` // true if account is currently selected (logged in)
+	Unlocked bool           `json:"unlocked"` // true if account has a session unlocked with UnlockSession()
+}
+
+// Zero overwrites private key material of the account, the key
+// must not be used afterwards.
+func (k *SelectedExtKey) Zero() {
+	if k != nil && k.AccountKey != nil {
+		zeroKey(k.AccountKey)
+	}
+}
+
 // Hex dumps address of a given extended key as hex string.
 func (k *SelectedExtKey) Hex() string {
 	if k == nil {
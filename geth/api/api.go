@@ -2,15 +2,22 @@ package api
 
 import (
 	"context"
+	"math/big"
+	"time"
 
 	"github.com/NaySoftware/go-fcm"
+	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv6"
 	"github.com/status-im/status-go/geth/account"
 	"github.com/status-im/status-go/geth/jail"
 	"github.com/status-im/status-go/geth/node"
 	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/rpc"
 	"github.com/status-im/status-go/geth/transactions"
 )
 
@@ -76,6 +83,12 @@ func (api *StatusAPI) StopNodeAsync() <-chan error {
 	return runAsync(api.StopNode)
 }
 
+// NodeInfo returns information about the running node, e.g. its enode URL,
+// which can be shared to be added as a static peer.
+func (api *StatusAPI) NodeInfo() (node.NodeInfo, error) {
+	return api.b.NodeInfo()
+}
+
 // RestartNode restart running Status node, fails if node is not running
 func (api *StatusAPI) RestartNode() error {
 	return api.b.RestartNode()
@@ -102,6 +115,40 @@ func (api *StatusAPI) CallRPC(inputJSON string) string {
 	return api.b.CallRPC(inputJSON)
 }
 
+// PauseBroadcast makes completed transactions to be signed, but not sent to the network
+// until ResumeBroadcast is called.
+func (api *StatusAPI) PauseBroadcast() {
+	api.b.PauseBroadcast()
+}
+
+// ResumeBroadcast sends transactions held while broadcasting was paused, in nonce order.
+func (api *StatusAPI) ResumeBroadcast() map[gethcommon.Hash]error {
+	return api.b.ResumeBroadcast()
+}
+
+// SetFiatPriceProvider sets provider of the native token price, which is used to
+// require additional confirmation of transactions with fee over the configured fiat cap.
+func (api *StatusAPI) SetFiatPriceProvider(provider transactions.FiatPriceProvider) {
+	api.b.SetFiatPriceProvider(provider)
+}
+
+// RegisterRPCHandler registers custom handler of RPC method, which is served by CallRPC.
+func (api *StatusAPI) RegisterRPCHandler(method string, handler rpc.Handler) {
+	api.b.RegisterRPCHandler(method, handler)
+}
+
+// ConfirmChainSwitch approves or rejects the chain switch requested with
+// wallet_switchEthereumChain, which is reported with chain.switch_requested signal.
+func (api *StatusAPI) ConfirmChainSwitch(id string, approved bool) error {
+	return api.b.ConfirmChainSwitch(id, approved)
+}
+
+// CallRPCTyped executes RPC request on node's in-proc RPC server and
+// returns decoded response
+func (api *StatusAPI) CallRPCTyped(request RPCRequest) (RPCResponse, error) {
+	return api.b.CallRPCTyped(request)
+}
+
 // CreateAccount creates an internal geth account
 // BIP44-compatible keys are generated: CKD#1 is stored as account key, CKD#2 stored as sub-account root
 // Public key of CKD#1 is returned, with CKD#2 securely encoded into account key file (to be used for
@@ -145,11 +192,94 @@ func (api *StatusAPI) Logout() error {
 	return api.b.Logout()
 }
 
+// GetLogs returns logs matching the given query.
+func (api *StatusAPI) GetLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return api.b.GetLogs(ctx, query)
+}
+
+// GetTokenTransfers returns Transfer events of the token sent from or to the account.
+func (api *StatusAPI) GetTokenTransfers(ctx context.Context, token, account gethcommon.Address, fromBlock, toBlock *big.Int) ([]types.Log, error) {
+	return api.b.GetTokenTransfers(ctx, token, account, fromBlock, toBlock)
+}
+
+// PingUpstream returns latency of the upstream RPC server.
+func (api *StatusAPI) PingUpstream(ctx context.Context) (time.Duration, error) {
+	return api.b.PingUpstream(ctx)
+}
+
+// GetContractAddress returns an address of the contract deployed by the mined transaction.
+func (api *StatusAPI) GetContractAddress(ctx context.Context, hash gethcommon.Hash) (gethcommon.Address, error) {
+	return api.b.GetContractAddress(ctx, hash)
+}
+
+// GetTransactionFee returns a fee actually paid by the mined transaction.
+func (api *StatusAPI) GetTransactionFee(ctx context.Context, hash gethcommon.Hash) (rpc.TransactionFee, error) {
+	return api.b.GetTransactionFee(ctx, hash)
+}
+
+// GetPendingTransactions returns transactions of the account which are not mined yet.
+func (api *StatusAPI) GetPendingTransactions(ctx context.Context, address gethcommon.Address) ([]rpc.PendingTx, error) {
+	return api.b.GetPendingTransactions(ctx, address)
+}
+
+// SubscribeLogs streams new logs matching the query until the returned function is called.
+func (api *StatusAPI) SubscribeLogs(ctx context.Context, query ethereum.FilterQuery) (<-chan types.Log, func(), error) {
+	return api.b.SubscribeLogs(ctx, query)
+}
+
+// PostWhisperMessage posts a Whisper message with default TTL and PoW target if they aren't specified.
+func (api *StatusAPI) PostWhisperMessage(ctx context.Context, msg whisper.NewMessage) error {
+	return api.b.PostWhisperMessage(ctx, msg)
+}
+
 // SendTransaction creates a new transaction and waits until it's complete.
 func (api *StatusAPI) SendTransaction(ctx context.Context, args transactions.SendTxArgs) (gethcommon.Hash, error) {
 	return api.b.SendTransaction(ctx, args)
 }
 
+// SendTransactionAsync creates a new transaction and returns its id without waiting for completion.
+func (api *StatusAPI) SendTransactionAsync(ctx context.Context, args transactions.SendTxArgs) (string, error) {
+	return api.b.SendTransactionAsync(ctx, args)
+}
+
+// ClearGasUsageCache forgets gas used by previous contract calls.
+func (api *StatusAPI) ClearGasUsageCache() {
+	api.b.ClearGasUsageCache()
+}
+
+// SetTransactionApprover sets approver which is invoked for every queued transaction.
+// Passing nil restores the default signal-based flow.
+func (api *StatusAPI) SetTransactionApprover(approver TransactionApprover) {
+	api.b.SetTransactionApprover(approver)
+}
+
+// Shutdown gracefully stops the node, waiting for transactions
+// which are being completed until context is done
+func (api *StatusAPI) Shutdown(ctx context.Context) error {
+	return api.b.Shutdown(ctx)
+}
+
+// QueueLength returns the number of queued transactions
+func (api *StatusAPI) QueueLength() int {
+	return api.b.QueueLength()
+}
+
+// RegisterSigner sets external signer, e.g. a hardware wallet, for the given account.
+// Passing nil restores signing with the keystore.
+func (api *StatusAPI) RegisterSigner(address gethcommon.Address, signer transactions.Signer) {
+	api.b.RegisterSigner(address, signer)
+}
+
+// IsNodeSynced checks whether the node is synced enough to send transactions
+func (api *StatusAPI) IsNodeSynced() (bool, error) {
+	return api.b.IsNodeSynced()
+}
+
+// UnlockSession allows completing transactions of the account without a password for the duration.
+func (api *StatusAPI) UnlockSession(address, password string, duration time.Duration) error {
+	return api.b.UnlockSession(address, password, duration)
+}
+
 // CompleteTransaction instructs backend to complete sending of a given transaction
 func (api *StatusAPI) CompleteTransaction(id string, password string) (gethcommon.Hash, error) {
 	return api.b.CompleteTransaction(id, password)
@@ -160,6 +290,38 @@ func (api *StatusAPI) CompleteTransactions(ids []string, password string) map[st
 	return api.b.CompleteTransactions(ids, password)
 }
 
+// BlockNumber returns the number of the latest block
+func (api *StatusAPI) BlockNumber(ctx context.Context) (uint64, error) {
+	return api.b.BlockNumber(ctx)
+}
+
+// NonceStatus returns confirmed, pending and local nonces of the given account
+func (api *StatusAPI) NonceStatus(ctx context.Context, address gethcommon.Address) (transactions.NonceStatus, error) {
+	return api.b.NonceStatus(ctx, address)
+}
+
+// RefreshNonce resets the local nonce of the account to the pending nonce known to the network.
+func (api *StatusAPI) RefreshNonce(ctx context.Context, address gethcommon.Address) error {
+	return api.b.RefreshNonce(ctx, address)
+}
+
+// SendRawTransaction broadcasts a transaction signed outside of the node
+func (api *StatusAPI) SendRawTransaction(ctx context.Context, signedTx hexutil.Bytes) (gethcommon.Hash, error) {
+	return api.b.SendRawTransaction(ctx, signedTx)
+}
+
+// ExportPendingRequests returns queued transactions in a form suitable
+// for signing by an external signer
+func (api *StatusAPI) ExportPendingRequests() ([]transactions.SerializedSignRequest, error) {
+	return api.b.ExportPendingRequests()
+}
+
+// ImportSignedResult sends a transaction signed by an external signer
+// for a given queued transaction
+func (api *StatusAPI) ImportSignedResult(ctx context.Context, id string, signedRaw hexutil.Bytes) (gethcommon.Hash, error) {
+	return api.b.ImportSignedResult(ctx, id, signedRaw)
+}
+
 // DiscardTransaction discards a given transaction from transaction queue
 func (api *StatusAPI) DiscardTransaction(id string) error {
 	return api.b.DiscardTransaction(id)
@@ -170,6 +332,16 @@ func (api *StatusAPI) DiscardTransactions(ids []string) map[string]error {
 	return api.b.DiscardTransactions(ids)
 }
 
+// LastGasPrice returns the last gas price chosen for a transaction of the account.
+func (api *StatusAPI) LastGasPrice(address gethcommon.Address) (*hexutil.Big, bool) {
+	return api.b.LastGasPrice(address)
+}
+
+// DiscardAllTransactions discards all queued transactions and returns results per transaction id.
+func (api *StatusAPI) DiscardAllTransactions() map[string]error {
+	return api.b.DiscardAllTransactions()
+}
+
 // JailParse creates a new jail cell context, with the given chatID as identifier.
 // New context executes provided JavaScript code, right after the initialization.
 // DEPRECATED in favour of CreateAndInitCell.
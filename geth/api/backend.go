@@ -4,9 +4,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
+	"path/filepath"
 	"sync"
+	"time"
 
+	ethereum "github.com/ethereum/go-ethereum"
 	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/status-im/status-go/geth/account"
@@ -14,6 +20,7 @@ import (
 	"github.com/status-im/status-go/geth/node"
 	"github.com/status-im/status-go/geth/notifications/push/fcm"
 	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/rpc"
 	"github.com/status-im/status-go/geth/signal"
 	"github.com/status-im/status-go/geth/transactions"
 )
@@ -28,8 +35,15 @@ var (
 	ErrWhisperClearIdentitiesFailure = errors.New("failed to clear whisper identities")
 	// ErrWhisperIdentityInjectionFailure injecting whisper identities has failed.
 	ErrWhisperIdentityInjectionFailure = errors.New("failed to inject identity into Whisper")
+	// ErrWhisperPoWTooLow message PoW target is below minimum PoW accepted by the node.
+	ErrWhisperPoWTooLow = errors.New("message PoW target is below node's minimum PoW")
 )
 
+// TransactionApprover decides whether a queued transaction should be sent.
+// If approved, transaction is completed using returned password,
+// otherwise it is discarded.
+type TransactionApprover func(req transactions.SerializedSignRequest) (approve bool, password string)
+
 // StatusBackend implements Status.im service
 type StatusBackend struct {
 	mu              sync.Mutex
@@ -39,6 +53,8 @@ type StatusBackend struct {
 	jailManager     jail.Manager
 	newNotification fcm.NotificationConstructor
 	connectionState ConnectionState
+	rpcHandlers     map[string]rpc.Handler // custom handlers, registered on every node start
+	chainSwitches   *chainSwitchRequests   // wallet_switchEthereumChain requests waiting for confirmation
 	log             log.Logger
 }
 
@@ -48,7 +64,10 @@ func NewStatusBackend() *StatusBackend {
 
 	statusNode := node.New()
 	accountManager := account.NewManager(statusNode)
+	accountManager.OnSessionEnd(account.NotifyOnSessionEnd)
 	txQueueManager := transactions.NewManager(statusNode)
+	txQueueManager.SetSelectedAccountProvider(accountManager)
+	txQueueManager.SetSyncStateProvider(statusNode)
 	jailManager := jail.New(statusNode)
 	notificationManager := fcm.NewNotification(fcmServerKey)
 
@@ -58,6 +77,8 @@ func NewStatusBackend() *StatusBackend {
 		jailManager:     jailManager,
 		txQueueManager:  txQueueManager,
 		newNotification: notificationManager,
+		rpcHandlers:     make(map[string]rpc.Handler),
+		chainSwitches:   newChainSwitchRequests(),
 		log:             log.New("package", "status-go/geth/api.StatusBackend"),
 	}
 }
@@ -87,6 +108,11 @@ func (b *StatusBackend) IsNodeRunning() bool {
 	return b.statusNode.IsRunning()
 }
 
+// NodeInfo returns information about the running node, e.g. its enode URL.
+func (b *StatusBackend) NodeInfo() (node.NodeInfo, error) {
+	return b.statusNode.NodeInfo()
+}
+
 // StartNode start Status node, fails if node is already started
 func (b *StatusBackend) StartNode(config *params.NodeConfig) error {
 	b.mu.Lock()
@@ -119,6 +145,12 @@ func (b *StatusBackend) startNode(config *params.NodeConfig) (err error) {
 	signal.Send(signal.Envelope{Type: signal.EventNodeStarted})
 	// tx queue manager should be started after node is started, it depends
 	// on rpc client being created
+	if config.TransactionsConfig != nil {
+		b.txQueueManager.Configure(*config.TransactionsConfig)
+	}
+	if err := b.txQueueManager.LoadGasPrices(filepath.Join(config.DataDir, params.GasPricesFile)); err != nil {
+		b.log.Warn("Failed to load gas prices", "err", err)
+	}
 	b.txQueueManager.Start(config.NetworkID)
 	if err := b.registerHandlers(); err != nil {
 		b.log.Error("Handler registration failed", "err", err)
@@ -138,6 +170,21 @@ func (b *StatusBackend) StopNode() error {
 	return b.stopNode()
 }
 
+// Shutdown gracefully stops the node. New transactions are refused at once,
+// transactions which are being completed are waited for until context is done,
+// and the rest of the queue is discarded before the node is stopped.
+func (b *StatusBackend) Shutdown(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.IsNodeRunning() {
+		return node.ErrNoRunningNode
+	}
+	if err := b.txQueueManager.Drain(ctx); err != nil {
+		b.log.Warn("transactions are not completed before shutdown", "err", err)
+	}
+	return b.stopNode()
+}
+
 func (b *StatusBackend) stopNode() error {
 	if !b.IsNodeRunning() {
 		return node.ErrNoRunningNode
@@ -191,22 +238,214 @@ func (b *StatusBackend) CallRPC(inputJSON string) string {
 	return client.CallRaw(inputJSON)
 }
 
+// CallRPCTyped executes RPC request on node's in-proc RPC server and decodes
+// the response. JSON-RPC error, if any, is returned as *rpc.Error, which
+// exposes error data, e.g. revert data of eth_call.
+func (b *StatusBackend) CallRPCTyped(request RPCRequest) (RPCResponse, error) {
+	client := b.statusNode.RPCClient()
+	if client == nil {
+		return RPCResponse{}, node.ErrRPCClient
+	}
+	return callRPCTyped(client, request)
+}
+
+// IsNodeSynced checks whether the node is synced enough to send transactions.
+// Node which uses upstream is always considered synced.
+func (b *StatusBackend) IsNodeSynced() (bool, error) {
+	config, err := b.statusNode.Config()
+	if err != nil {
+		return false, err
+	}
+	maxLag := uint64(params.DefaultMaxSyncLag)
+	if config.TransactionsConfig != nil {
+		maxLag = config.TransactionsConfig.MaxSyncLag
+	}
+	return b.statusNode.IsSynced(maxLag)
+}
+
+// BlockNumber returns the number of the latest block
+func (b *StatusBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client := b.statusNode.RPCClient()
+	if client == nil {
+		return 0, node.ErrRPCClient
+	}
+	return client.BlockNumber(ctx)
+}
+
+// GetLogs returns logs matching the given query. From block of the query is
+// required and the range of blocks is limited to rpc.MaxLogsBlockRange.
+func (b *StatusBackend) GetLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client := b.statusNode.RPCClient()
+	if client == nil {
+		return nil, node.ErrRPCClient
+	}
+	return client.GetLogs(ctx, query)
+}
+
+// GetTokenTransfers returns Transfer events of the token sent from or to the account
+// within the given range of blocks. Nil toBlock means the latest block, fromBlock
+// is required and the range is limited to rpc.MaxLogsBlockRange.
+func (b *StatusBackend) GetTokenTransfers(ctx context.Context, token, account gethcommon.Address, fromBlock, toBlock *big.Int) ([]types.Log, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client := b.statusNode.RPCClient()
+	if client == nil {
+		return nil, node.ErrRPCClient
+	}
+	return client.GetTokenTransfers(ctx, token, account, fromBlock, toBlock)
+}
+
+// PingUpstream returns latency of the upstream RPC server.
+func (b *StatusBackend) PingUpstream(ctx context.Context) (time.Duration, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client := b.statusNode.RPCClient()
+	if client == nil {
+		return 0, node.ErrRPCClient
+	}
+	return client.PingUpstream(ctx)
+}
+
+// GetContractAddress returns an address of the contract deployed by the mined transaction.
+func (b *StatusBackend) GetContractAddress(ctx context.Context, hash gethcommon.Hash) (gethcommon.Address, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client := b.statusNode.RPCClient()
+	if client == nil {
+		return gethcommon.Address{}, node.ErrRPCClient
+	}
+	return client.GetContractAddress(ctx, hash)
+}
+
+// GetTransactionFee returns a fee actually paid by the mined transaction,
+// which may be lower than its maximum fee.
+func (b *StatusBackend) GetTransactionFee(ctx context.Context, hash gethcommon.Hash) (rpc.TransactionFee, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client := b.statusNode.RPCClient()
+	if client == nil {
+		return rpc.TransactionFee{}, node.ErrRPCClient
+	}
+	return client.GetTransactionFee(ctx, hash)
+}
+
+// GetPendingTransactions returns transactions of the account which are not mined yet.
+// rpc.ErrPendingTransactionsUnsupported is returned if the upstream doesn't expose its pool.
+func (b *StatusBackend) GetPendingTransactions(ctx context.Context, address gethcommon.Address) ([]rpc.PendingTx, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client := b.statusNode.RPCClient()
+	if client == nil {
+		return nil, node.ErrRPCClient
+	}
+	return client.GetPendingTransactions(ctx, address)
+}
+
+// SubscribeLogs streams new logs matching the query until the returned function
+// is called or the context is done.
+func (b *StatusBackend) SubscribeLogs(ctx context.Context, query ethereum.FilterQuery) (<-chan types.Log, func(), error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	client := b.statusNode.RPCClient()
+	if client == nil {
+		return nil, nil, node.ErrRPCClient
+	}
+	return client.SubscribeLogs(ctx, query)
+}
+
 // SendTransaction creates a new transaction and waits until it's complete.
+// Returned error, if any, is of *transactions.TxError type.
 func (b *StatusBackend) SendTransaction(ctx context.Context, args transactions.SendTxArgs) (hash gethcommon.Hash, err error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 	tx := transactions.Create(ctx, args)
 	if err = b.txQueueManager.QueueTransaction(tx); err != nil {
-		return hash, err
+		return hash, transactions.NewTxError(tx.ID, err)
 	}
 	rst := b.txQueueManager.WaitForTransaction(tx)
 	if rst.Error != nil {
-		return hash, rst.Error
+		return hash, transactions.NewTxError(tx.ID, rst.Error)
 	}
 	return rst.Hash, nil
 }
 
+// SendTransactionAsync creates a new transaction and returns its id right after it's queued.
+// The outcome is reported with transaction.done signal. Returned error, if any,
+// is of *transactions.TxError type.
+func (b *StatusBackend) SendTransactionAsync(ctx context.Context, args transactions.SendTxArgs) (string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	tx := transactions.Create(ctx, args)
+	if err := b.txQueueManager.QueueTransaction(tx); err != nil {
+		return "", transactions.NewTxError(tx.ID, err)
+	}
+	// waiting is still required, so that transaction times out if it's never completed
+	go b.txQueueManager.WaitForTransaction(tx)
+	return tx.ID, nil
+}
+
+// ClearGasUsageCache forgets gas used by previous contract calls.
+func (b *StatusBackend) ClearGasUsageCache() {
+	b.txQueueManager.ClearGasUsageCache()
+}
+
+// SetTransactionApprover sets approver which is invoked for every queued transaction.
+// It is an alternative to completing or discarding transactions in response to
+// transaction.queued signal, useful for fully automated setups. While approver
+// is set, transaction.queued signals are not sent.
+// Passing nil restores the default signal-based flow.
+func (b *StatusBackend) SetTransactionApprover(approver TransactionApprover) {
+	if approver == nil {
+		b.txQueueManager.SetEnqueueHandler(nil)
+		return
+	}
+	b.txQueueManager.SetEnqueueHandler(func(tx *transactions.QueuedTx) {
+		go b.approveTransaction(approver, tx)
+	})
+}
+
+// RegisterSigner sets external signer, e.g. a hardware wallet, for the given account.
+// Passing nil restores signing with the keystore.
+func (b *StatusBackend) RegisterSigner(address gethcommon.Address, signer transactions.Signer) {
+	b.txQueueManager.RegisterSigner(address, signer)
+}
+
+// approveTransaction completes or discards transaction depending on approver's decision.
+// Approver isn't asked again, so the transaction which failed to complete is removed
+// from the queue with transaction.failed signal, even if the error is transient.
+func (b *StatusBackend) approveTransaction(approver TransactionApprover, tx *transactions.QueuedTx) {
+	approve, password := approver(transactions.NewSerializedSignRequest(tx))
+	if !approve {
+		if err := b.DiscardTransaction(tx.ID); err != nil {
+			b.log.Error("failed to discard unapproved transaction", "id", tx.ID, "err", err)
+		}
+		return
+	}
+	_, err := b.CompleteTransaction(tx.ID, password)
+	if err == nil || err == transactions.ErrBroadcastHeld {
+		return
+	}
+	b.log.Error("failed to complete approved transaction", "id", tx.ID, "err", err)
+	// transaction is already removed, unless the error is transient
+	if err := b.txQueueManager.FailTransaction(tx.ID, err); err != nil && err != transactions.ErrQueuedTxIDNotFound {
+		b.log.Error("failed to fail approved transaction", "id", tx.ID, "err", err)
+	}
+}
+
 func (b *StatusBackend) getVerifiedAccount(password string) (*account.SelectedExtKey, error) {
 	selectedAccount, err := b.accountManager.SelectedAccount()
 	if err != nil {
@@ -225,28 +464,132 @@ func (b *StatusBackend) getVerifiedAccount(password string) (*account.SelectedEx
 	return selectedAccount, nil
 }
 
+// transactionAccount returns an account to complete the given transaction with.
+// It is the selected account, unless the key of transaction sender is configured to be used.
+// Without a password, the sender's account is used if its session is unlocked.
+// If decrypted is true, the key is decrypted for this transaction only and must be
+// zeroed after use.
+func (b *StatusBackend) transactionAccount(id string, password string) (acc *account.SelectedExtKey, decrypted bool, err error) {
+	if password == "" {
+		tx, err := b.txQueueManager.TransactionQueue().Get(id)
+		if err != nil {
+			return nil, false, err
+		}
+		if sessionAccount, ok := b.accountManager.SessionAccount(tx.Args.From); ok {
+			return sessionAccount, true, nil
+		}
+	}
+
+	config, err := b.StatusNode().Config()
+	if err != nil {
+		return nil, false, err
+	}
+	if config.TransactionsConfig == nil || !config.TransactionsConfig.UseSenderAccount {
+		acc, err = b.getVerifiedAccount(password)
+		return acc, false, err
+	}
+
+	tx, err := b.txQueueManager.TransactionQueue().Get(id)
+	if err != nil {
+		return nil, false, err
+	}
+	senderAccount, accountKey, err := b.accountManager.AddressToDecryptedAccount(tx.Args.From.Hex(), password)
+	if err != nil {
+		b.log.Error("failed to decrypt sender account", "account", tx.Args.From.Hex(), "error", err)
+		return nil, false, fmt.Errorf("%s: %v", account.ErrAccountToKeyMappingFailure, err)
+	}
+	return &account.SelectedExtKey{
+		Address:    senderAccount.Address,
+		AccountKey: accountKey,
+	}, true, nil
+}
+
+// UnlockSession keeps the decrypted key of the account in memory for the duration,
+// so that its transactions can be completed with an empty password.
+func (b *StatusBackend) UnlockSession(address, password string, duration time.Duration) error {
+	return b.accountManager.UnlockSession(address, password, duration)
+}
+
 // CompleteTransaction instructs backend to complete sending of a given transaction
 func (b *StatusBackend) CompleteTransaction(id string, password string) (hash gethcommon.Hash, err error) {
-	selectedAccount, err := b.getVerifiedAccount(password)
+	result := b.completeTransaction(id, password)
+	return result.Hash, result.Error
+}
+
+// completeTransaction completes transaction and reports the stage at which it failed.
+func (b *StatusBackend) completeTransaction(id string, password string) transactions.Result {
+	selectedAccount, decrypted, err := b.transactionAccount(id, password)
 	if err != nil {
 		_ = b.txQueueManager.NotifyErrored(id, err)
-		return hash, err
+		return transactions.Result{Error: err, Stage: transactions.StageUnlock}
+	}
+	if decrypted {
+		defer selectedAccount.Zero()
 	}
 
-	return b.txQueueManager.CompleteTransaction(id, selectedAccount)
+	return b.txQueueManager.CompleteTransactionResult(id, selectedAccount)
+}
+
+// QueueLength returns the number of queued transactions
+func (b *StatusBackend) QueueLength() int {
+	return b.txQueueManager.TransactionQueue().Count()
 }
 
-// CompleteTransactions instructs backend to complete sending of multiple transactions
+// CompleteTransactions instructs backend to complete sending of multiple transactions.
+// Transactions are picked in order of their priority and at most TransactionsConfig.CompletionWorkers
+// of them are completed concurrently.
 func (b *StatusBackend) CompleteTransactions(ids []string, password string) map[string]transactions.Result {
-	results := make(map[string]transactions.Result)
-	for _, txID := range ids {
-		txHash, txErr := b.CompleteTransaction(txID, password)
-		results[txID] = transactions.Result{
-			Hash:  txHash,
-			Error: txErr,
-		}
+	workers := 1
+	if config, err := b.statusNode.Config(); err == nil && config.TransactionsConfig != nil {
+		workers = config.TransactionsConfig.CompletionWorkers
 	}
-	return results
+	ids = b.txQueueManager.TransactionQueue().SortByPriority(ids)
+	return completeConcurrently(ids, workers, func(id string) transactions.Result {
+		return b.completeTransaction(id, password)
+	})
+}
+
+// NonceStatus returns confirmed, pending and local nonces of the given account
+func (b *StatusBackend) NonceStatus(ctx context.Context, address gethcommon.Address) (transactions.NonceStatus, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return b.txQueueManager.NonceStatus(ctx, address)
+}
+
+// RefreshNonce resets the local nonce of the account to the pending nonce known to the network.
+func (b *StatusBackend) RefreshNonce(ctx context.Context, address gethcommon.Address) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return b.txQueueManager.RefreshNonce(ctx, address)
+}
+
+// SendRawTransaction broadcasts a transaction signed outside of the node.
+// The transaction must be signed for the current network.
+func (b *StatusBackend) SendRawTransaction(ctx context.Context, signedTx hexutil.Bytes) (gethcommon.Hash, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return b.txQueueManager.SendRawTransaction(ctx, signedTx)
+}
+
+// ExportPendingRequests returns queued transactions in a form suitable
+// for signing by an external signer
+func (b *StatusBackend) ExportPendingRequests() ([]transactions.SerializedSignRequest, error) {
+	if !b.IsNodeRunning() {
+		return nil, node.ErrNoRunningNode
+	}
+	return b.txQueueManager.ExportPendingRequests(), nil
+}
+
+// ImportSignedResult sends a transaction signed by an external signer
+// for a given queued transaction
+func (b *StatusBackend) ImportSignedResult(ctx context.Context, id string, signedRaw hexutil.Bytes) (gethcommon.Hash, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return b.txQueueManager.ImportSignedResult(ctx, id, signedRaw)
 }
 
 // DiscardTransaction discards a given transaction from transaction queue
@@ -267,6 +610,37 @@ func (b *StatusBackend) DiscardTransactions(ids []string) map[string]error {
 	return results
 }
 
+// LastGasPrice returns the last gas price chosen for a transaction of the account.
+func (b *StatusBackend) LastGasPrice(address gethcommon.Address) (*hexutil.Big, bool) {
+	return b.txQueueManager.LastGasPrice(address)
+}
+
+// DiscardAllTransactions discards all queued transactions, except for those
+// which are being completed, and returns results per transaction id.
+func (b *StatusBackend) DiscardAllTransactions() map[string]error {
+	return b.txQueueManager.DiscardAllTransactions()
+}
+
+// PauseBroadcast makes completed transactions to be signed, but not sent to the network
+// until ResumeBroadcast is called. Completion of such transactions returns
+// transactions.ErrBroadcastHeld and transaction.signed_pending_broadcast signal is sent
+// for every held transaction.
+func (b *StatusBackend) PauseBroadcast() {
+	b.txQueueManager.PauseBroadcast()
+}
+
+// ResumeBroadcast sends transactions held while broadcasting was paused, in nonce order,
+// and returns results per transaction hash. Held transactions leave the queue with these results.
+func (b *StatusBackend) ResumeBroadcast() map[gethcommon.Hash]error {
+	return b.txQueueManager.ResumeBroadcast()
+}
+
+// SetFiatPriceProvider sets provider of the native token price, which is used to
+// require additional confirmation of transactions with fee over the configured fiat cap.
+func (b *StatusBackend) SetFiatPriceProvider(provider transactions.FiatPriceProvider) {
+	b.txQueueManager.SetFiatPriceProvider(provider)
+}
+
 // registerHandlers attaches Status callback handlers to running node
 func (b *StatusBackend) registerHandlers() error {
 	rpcClient := b.StatusNode().RPCClient()
@@ -277,9 +651,26 @@ func (b *StatusBackend) registerHandlers() error {
 		return b.AccountManager().Accounts()
 	})
 	rpcClient.RegisterHandler("eth_sendTransaction", b.txQueueManager.SendTransactionRPCHandler)
+	rpcClient.RegisterHandler("wallet_switchEthereumChain", b.switchEthereumChainHandler)
+	for method, handler := range b.rpcHandlers {
+		rpcClient.RegisterHandler(method, handler)
+	}
 	return nil
 }
 
+// RegisterRPCHandler registers custom handler of RPC method, e.g. status_* methods,
+// which is served by CallRPC instead of the upstream or the local node.
+// Handler stays registered after the node is restarted.
+func (b *StatusBackend) RegisterRPCHandler(method string, handler rpc.Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rpcHandlers[method] = handler
+	if rpcClient := b.statusNode.RPCClient(); rpcClient != nil {
+		rpcClient.RegisterHandler(method, handler)
+	}
+}
+
 // ConnectionChange handles network state changes logic.
 func (b *StatusBackend) ConnectionChange(state ConnectionState) {
 	b.log.Info("Network state change", "old", b.connectionState, "new", state)
@@ -297,7 +688,8 @@ func (b *StatusBackend) AppStateChange(state AppState) {
 	// and normal mode if the app is in foreground.
 }
 
-// Logout clears whisper identities.
+// Logout clears whisper identities and discards queued transactions of the selected
+// account and accounts with unlocked sessions, which are ended.
 func (b *StatusBackend) Logout() error {
 	whisperService, err := b.statusNode.WhisperService()
 	if err != nil {
@@ -308,6 +700,15 @@ func (b *StatusBackend) Logout() error {
 		return fmt.Errorf("%s: %v", ErrWhisperClearIdentitiesFailure, err)
 	}
 
+	// transactions of the logged out accounts can't be completed anymore
+	loggedOut := b.AccountManager().SessionAccounts()
+	if selectedAccount, err := b.AccountManager().SelectedAccount(); err == nil {
+		loggedOut = append(loggedOut, selectedAccount.Address)
+	}
+	for _, address := range loggedOut {
+		b.txQueueManager.DiscardTransactionsFrom(address, transactions.ErrAccountLoggedOut)
+	}
+
 	return b.AccountManager().Logout()
 }
 
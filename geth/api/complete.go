@@ -0,0 +1,44 @@
+package api
+
+import (
+	"sync"
+
+	"github.com/status-im/status-go/geth/transactions"
+)
+
+// completeConcurrently completes transactions with at most workers of them completed
+// at the same time. Transactions are picked in the given order, so with a single
+// worker they are completed one by one in that order.
+func completeConcurrently(ids []string, workers int, complete func(id string) transactions.Result) map[string]transactions.Result {
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(ids) {
+		workers = len(ids)
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string]transactions.Result, len(ids))
+		queue   = make(chan string)
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range queue {
+				result := complete(id)
+				mu.Lock()
+				results[id] = result
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, id := range ids {
+		queue <- id
+	}
+	close(queue)
+	wg.Wait()
+	return results
+}
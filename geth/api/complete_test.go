@@ -0,0 +1,56 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/status-im/status-go/geth/transactions"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteConcurrently(t *testing.T) {
+	ids := make([]string, 30)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("tx%d", i)
+	}
+	var running, maxRunning int32
+	errFailed := errors.New("failed")
+	complete := func(id string) transactions.Result {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		if id == "tx7" {
+			return transactions.Result{Error: errFailed}
+		}
+		return transactions.Result{}
+	}
+
+	results := completeConcurrently(ids, 4, complete)
+	require.Len(t, results, len(ids))
+	require.Equal(t, errFailed, results["tx7"].Error)
+	require.NoError(t, results["tx8"].Error)
+	require.True(t, maxRunning <= 4, "too many concurrent completions: %d", maxRunning)
+
+	// single worker keeps the order
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	completeConcurrently(ids, 0, func(id string) transactions.Result {
+		mu.Lock()
+		order = append(order, id)
+		mu.Unlock()
+		return transactions.Result{}
+	})
+	require.Equal(t, ids, order)
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/status-im/status-go/geth/rpc"
+)
+
+// RPCRequest is a JSON-RPC request passed to CallRPCTyped.
+type RPCRequest struct {
+	ID     json.RawMessage `json:"id,omitempty"`
+	Method string          `json:"method"`
+	Params []interface{}   `json:"params,omitempty"`
+}
+
+// RPCResponse is a JSON-RPC response returned by CallRPCTyped.
+type RPCResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *rpc.Error      `json:"error,omitempty"`
+}
+
+// marshalRPCRequest encodes request into JSON-RPC body.
+func marshalRPCRequest(request RPCRequest) (string, error) {
+	body, err := json.Marshal(struct {
+		Version string `json:"jsonrpc"`
+		RPCRequest
+	}{rpc.JSONRPCVersion, request})
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// callRPCTyped executes request with the client and decodes the response.
+func callRPCTyped(client *rpc.Client, request RPCRequest) (RPCResponse, error) {
+	body, err := marshalRPCRequest(request)
+	if err != nil {
+		return RPCResponse{}, err
+	}
+	return parseRPCResponse(client.CallRaw(body))
+}
+
+// parseRPCResponse decodes JSON-RPC response. If response contains
+// an error, it is returned as *rpc.Error.
+func parseRPCResponse(raw string) (RPCResponse, error) {
+	var response RPCResponse
+	if err := json.Unmarshal([]byte(raw), &response); err != nil {
+		return response, err
+	}
+	if response.Error != nil {
+		return response, response.Error
+	}
+	return response, nil
+}
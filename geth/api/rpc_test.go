@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalRPCRequest(t *testing.T) {
+	body, err := marshalRPCRequest(RPCRequest{
+		ID:     json.RawMessage(`1`),
+		Method: "eth_getBalance",
+		Params: []interface{}{"0x01", "latest"},
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"jsonrpc":"2.0","id":1,"method":"eth_getBalance","params":["0x01","latest"]}`, body)
+}
+
+func TestParseRPCResponse(t *testing.T) {
+	response, err := parseRPCResponse(`{"jsonrpc":"2.0","id":1,"result":"0x10"}`)
+	require.NoError(t, err)
+	require.Nil(t, response.Error)
+	require.Equal(t, `"0x10"`, string(response.Result))
+
+	response, err = parseRPCResponse(`{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"method not found"}}`)
+	require.Error(t, err)
+	rpcErr, ok := err.(*rpc.Error)
+	require.True(t, ok)
+	require.Equal(t, -32601, rpcErr.Code)
+	require.Equal(t, "method not found", rpcErr.Message)
+	require.Equal(t, rpcErr, response.Error)
+
+	_, err = parseRPCResponse(`not json`)
+	require.Error(t, err)
+}
+
+func TestCallRPCTypedRevertData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted","data":"0x08c379a0"}}`)) //nolint: errcheck
+	}))
+	defer server.Close()
+
+	client, err := rpc.NewClient(nil, params.UpstreamRPCConfig{Enabled: true, URL: server.URL})
+	require.NoError(t, err)
+
+	response, err := callRPCTyped(client, RPCRequest{
+		ID:     json.RawMessage(`1`),
+		Method: "eth_call",
+		Params: []interface{}{map[string]interface{}{}, "latest"},
+	})
+	rpcErr, ok := err.(*rpc.Error)
+	require.True(t, ok, "unexpected error: %v", err)
+	require.Equal(t, rpcErr, response.Error)
+	require.Equal(t, 3, rpcErr.Code)
+	data, ok := rpcErr.RevertData()
+	require.True(t, ok)
+	require.Equal(t, []byte{0x08, 0xc3, 0x79, 0xa0}, data)
+}
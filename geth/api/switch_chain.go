@@ -0,0 +1,190 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pborman/uuid"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+const (
+	// unrecognizedChainErrorCode is returned by wallet_switchEthereumChain
+	// for chains which are not configured (EIP-3326).
+	unrecognizedChainErrorCode = 4902
+
+	// userRejectedErrorCode is returned when the user rejects a request (EIP-1193).
+	userRejectedErrorCode = 4001
+
+	// ChainSwitchTimeout is how long wallet_switchEthereumChain waits
+	// for the app to confirm the switch.
+	ChainSwitchTimeout = 5 * time.Minute
+)
+
+var (
+	// ErrInvalidSwitchChainParams is returned when wallet_switchEthereumChain
+	// is called without a valid chain id.
+	ErrInvalidSwitchChainParams = errors.New("invalid wallet_switchEthereumChain params")
+
+	// ErrChainSwitchNotFound is returned when confirmed chain switch
+	// is not waiting for confirmation.
+	ErrChainSwitchNotFound = errors.New("chain switch request not found")
+)
+
+// UnrecognizedChainError is returned when requested chain id does not have
+// a configured upstream.
+type UnrecognizedChainError struct {
+	ChainID uint64
+}
+
+func (e UnrecognizedChainError) Error() string {
+	return fmt.Sprintf("unrecognized chain ID %#x", e.ChainID)
+}
+
+// ErrorCode returns JSON-RPC error code of the error.
+func (e UnrecognizedChainError) ErrorCode() int {
+	return unrecognizedChainErrorCode
+}
+
+// ChainSwitchRejectedError is returned when the chain switch is rejected
+// by the user or isn't confirmed in time.
+type ChainSwitchRejectedError struct{}
+
+func (e ChainSwitchRejectedError) Error() string {
+	return "chain switch is rejected by the user"
+}
+
+// ErrorCode returns JSON-RPC error code of the error.
+func (e ChainSwitchRejectedError) ErrorCode() int {
+	return userRejectedErrorCode
+}
+
+// chainSwitchRequests keeps chain switches waiting for the app's confirmation.
+type chainSwitchRequests struct {
+	mu      sync.Mutex // guards pending
+	pending map[string]chan bool
+}
+
+func newChainSwitchRequests() *chainSwitchRequests {
+	return &chainSwitchRequests{
+		pending: make(map[string]chan bool),
+	}
+}
+
+// request asks the app to confirm the switch with chain.switch_requested
+// signal and waits until it's confirmed, rejected or times out.
+func (r *chainSwitchRequests) request(ctx context.Context, chainID uint64, url string, timeout time.Duration) error {
+	id := uuid.New()
+	decision := make(chan bool, 1)
+	r.mu.Lock()
+	r.pending[id] = decision
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.pending, id)
+		r.mu.Unlock()
+	}()
+
+	signal.Send(signal.Envelope{
+		Type: signal.EventChainSwitchRequested,
+		Event: signal.ChainSwitchRequestedEvent{
+			ID:      id,
+			ChainID: chainID,
+			URL:     url,
+		},
+	})
+
+	select {
+	case approved := <-decision:
+		if !approved {
+			return ChainSwitchRejectedError{}
+		}
+		return nil
+	case <-time.After(timeout):
+		return ChainSwitchRejectedError{}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// confirm delivers the app's decision on the switch with the given id.
+func (r *chainSwitchRequests) confirm(id string, approved bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	decision, ok := r.pending[id]
+	if !ok {
+		return ErrChainSwitchNotFound
+	}
+	delete(r.pending, id)
+	decision <- approved
+	return nil
+}
+
+// switchChainParams extracts chain id from wallet_switchEthereumChain params,
+// which are `[{"chainId": "0x1"}]`.
+func switchChainParams(args ...interface{}) (uint64, error) {
+	if len(args) != 1 {
+		return 0, ErrInvalidSwitchChainParams
+	}
+	params, ok := args[0].(map[string]interface{})
+	if !ok {
+		return 0, ErrInvalidSwitchChainParams
+	}
+	chainID, ok := params["chainId"].(string)
+	if !ok {
+		return 0, ErrInvalidSwitchChainParams
+	}
+	id, err := hexutil.DecodeUint64(chainID)
+	if err != nil {
+		return 0, ErrInvalidSwitchChainParams
+	}
+	return id, nil
+}
+
+// switchEthereumChainHandler switches upstream to the one configured for
+// requested chain id, once the app confirmed the switch. Upstream must serve
+// the requested chain. Transactions are sent to the new chain afterwards.
+func (b *StatusBackend) switchEthereumChainHandler(ctx context.Context, args ...interface{}) (interface{}, error) {
+	chainID, err := switchChainParams(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := b.StatusNode().Config()
+	if err != nil {
+		return nil, err
+	}
+	url, ok := config.UpstreamConfig.ChainURLs[chainID]
+	if !ok {
+		return nil, UnrecognizedChainError{ChainID: chainID}
+	}
+
+	if err := b.chainSwitches.request(ctx, chainID, url, ChainSwitchTimeout); err != nil {
+		return nil, err
+	}
+	if err := b.StatusNode().RPCClient().SwitchUpstreamChain(ctx, url, chainID); err != nil {
+		return nil, err
+	}
+	b.txQueueManager.SwitchNetwork(chainID)
+
+	signal.Send(signal.Envelope{
+		Type: signal.EventChainSwitched,
+		Event: signal.ChainSwitchedEvent{
+			ChainID: chainID,
+			URL:     url,
+		},
+	})
+
+	return nil, nil
+}
+
+// ConfirmChainSwitch approves or rejects the chain switch requested by a dapp
+// with wallet_switchEthereumChain, which is reported with chain.switch_requested signal.
+func (b *StatusBackend) ConfirmChainSwitch(id string, approved bool) error {
+	return b.chainSwitches.confirm(id, approved)
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwitchChainParams(t *testing.T) {
+	chainID, err := switchChainParams(map[string]interface{}{"chainId": "0x3"})
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), chainID)
+
+	testCases := [][]interface{}{
+		{},
+		{"0x3"},
+		{map[string]interface{}{}},
+		{map[string]interface{}{"chainId": "3"}},
+		{map[string]interface{}{"chainId": "0x3"}, "0x4"},
+	}
+	for _, args := range testCases {
+		_, err := switchChainParams(args...)
+		require.Equal(t, ErrInvalidSwitchChainParams, err, "args: %v", args)
+	}
+}
+
+func TestUnrecognizedChainError(t *testing.T) {
+	var err error = UnrecognizedChainError{ChainID: 5}
+	rpcErr, ok := err.(gethrpc.Error)
+	require.True(t, ok)
+	require.Equal(t, 4902, rpcErr.ErrorCode())
+	require.Equal(t, "unrecognized chain ID 0x5", err.Error())
+}
+
+func TestChainSwitchConfirmation(t *testing.T) {
+	requests := newChainSwitchRequests()
+	decisions := make(chan bool, 1)
+	remove := signal.AddHandler(func(envelope signal.Envelope) {
+		if envelope.Type != signal.EventChainSwitchRequested {
+			return
+		}
+		// the app decides right away, unless there is no decision
+		select {
+		case approved := <-decisions:
+			event := envelope.Event.(signal.ChainSwitchRequestedEvent)
+			require.NoError(t, requests.confirm(event.ID, approved))
+		default:
+		}
+	})
+	defer remove()
+
+	decisions <- true
+	require.NoError(t, requests.request(context.Background(), 3, "https://ropsten.infura.io", time.Second))
+
+	decisions <- false
+	err := requests.request(context.Background(), 3, "https://ropsten.infura.io", time.Second)
+	require.Equal(t, ChainSwitchRejectedError{}, err)
+	require.Equal(t, 4001, err.(gethrpc.Error).ErrorCode())
+
+	// not confirmed in time
+	require.Equal(t, ChainSwitchRejectedError{}, requests.request(context.Background(), 3, "https://ropsten.infura.io", 10*time.Millisecond))
+	require.Empty(t, requests.pending)
+
+	require.Equal(t, ErrChainSwitchNotFound, requests.confirm("unknown", true))
+}
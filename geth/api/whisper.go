@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv6"
+	"github.com/status-im/status-go/geth/params"
+)
+
+// PostWhisperMessage posts a Whisper message. Message TTL and PoW target,
+// if not specified, are taken from the Whisper config.
+func (b *StatusBackend) PostWhisperMessage(ctx context.Context, msg whisper.NewMessage) error {
+	whisperService, err := b.statusNode.WhisperService()
+	if err != nil {
+		return err
+	}
+	config, err := b.statusNode.Config()
+	if err != nil {
+		return err
+	}
+	if err := applyWhisperDefaults(&msg, config.WhisperConfig, whisperService.MinPow()); err != nil {
+		return err
+	}
+	_, err = whisper.NewPublicWhisperAPI(whisperService).Post(ctx, msg)
+	return err
+}
+
+// applyWhisperDefaults sets TTL and PoW target which are not specified
+// in the message and makes sure that the message is not going to be
+// dropped because of too low PoW. Messages sent to a specific peer
+// are not checked, as their PoW is not verified.
+func applyWhisperDefaults(msg *whisper.NewMessage, config *params.WhisperConfig, minPoW float64) error {
+	if config != nil {
+		if msg.TTL == 0 {
+			msg.TTL = uint32(config.TTL)
+		}
+		if msg.PowTarget == 0 {
+			msg.PowTarget = config.PoWTarget
+		}
+	}
+	if len(msg.TargetPeer) == 0 && msg.PowTarget < minPoW {
+		return fmt.Errorf("%v: %v < %v", ErrWhisperPoWTooLow, msg.PowTarget, minPoW)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"testing"
+
+	whisper "github.com/ethereum/go-ethereum/whisper/whisperv6"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyWhisperDefaults(t *testing.T) {
+	config := &params.WhisperConfig{TTL: 120, PoWTarget: 0.2}
+
+	msg := whisper.NewMessage{}
+	require.NoError(t, applyWhisperDefaults(&msg, config, 0.2))
+	require.Equal(t, uint32(120), msg.TTL)
+	require.Equal(t, 0.2, msg.PowTarget)
+
+	// explicit values are kept
+	msg = whisper.NewMessage{TTL: 10, PowTarget: 0.5}
+	require.NoError(t, applyWhisperDefaults(&msg, config, 0.2))
+	require.Equal(t, uint32(10), msg.TTL)
+	require.Equal(t, 0.5, msg.PowTarget)
+
+	msg = whisper.NewMessage{}
+	err := applyWhisperDefaults(&msg, config, 0.3)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), ErrWhisperPoWTooLow.Error())
+
+	// PoW is not checked for messages to a specific peer
+	msg = whisper.NewMessage{TargetPeer: "enode://"}
+	require.NoError(t, applyWhisperDefaults(&msg, config, 0.3))
+}
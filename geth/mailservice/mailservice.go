@@ -1,6 +1,8 @@
 package mailservice
 
 import (
+	"time"
+
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -15,15 +17,16 @@ type ServiceProvider interface {
 
 // MailService is a service that provides some additional Whisper API.
 type MailService struct {
-	provider ServiceProvider
+	provider    ServiceProvider
+	peerTimeout time.Duration // how long requests wait for MailServer peer to connect
 }
 
 // Make sure that MailService implements node.Service interface.
 var _ node.Service = (*MailService)(nil)
 
 // New returns a new MailService.
-func New(provider ServiceProvider) *MailService {
-	return &MailService{provider}
+func New(provider ServiceProvider, peerTimeout time.Duration) *MailService {
+	return &MailService{provider, peerTimeout}
 }
 
 // Protocols returns a new protocols list. In this case, there are none.
@@ -37,7 +40,7 @@ func (s *MailService) APIs() []rpc.API {
 		{
 			Namespace: "shh",
 			Version:   "1.0",
-			Service:   NewPublicAPI(s.provider),
+			Service:   NewPublicAPI(s.provider, s.peerTimeout),
 			Public:    true,
 		},
 	}
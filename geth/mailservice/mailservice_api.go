@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/discover"
 
 	whisper "github.com/ethereum/go-ethereum/whisper/whisperv6"
@@ -24,19 +25,24 @@ var (
 	ErrInvalidMailServerPeer = errors.New("invalid mailServerPeer value")
 	// ErrInvalidSymKeyID is returned when it fails to get a symmetric key.
 	ErrInvalidSymKeyID = errors.New("invalid symKeyID value")
+	// ErrMailServerPeerTimeout is returned when MailServer peer didn't connect in time.
+	ErrMailServerPeerTimeout = errors.New("timed out waiting for mailServerPeer to connect")
 )
 
 // PublicAPI defines a MailServer public API.
 type PublicAPI struct {
-	provider ServiceProvider
-	log      log.Logger
+	provider    ServiceProvider
+	peerTimeout time.Duration
+	log         log.Logger
 }
 
-// NewPublicAPI returns a new PublicAPI.
-func NewPublicAPI(provider ServiceProvider) *PublicAPI {
+// NewPublicAPI returns a new PublicAPI. Requests wait up to peerTimeout
+// for MailServer peer to connect.
+func NewPublicAPI(provider ServiceProvider, peerTimeout time.Duration) *PublicAPI {
 	return &PublicAPI{
-		provider: provider,
-		log:      log.New("package", "status-go/geth/mailservice.PublicAPI"),
+		provider:    provider,
+		peerTimeout: peerTimeout,
+		log:         log.New("package", "status-go/geth/mailservice.PublicAPI"),
 	}
 }
 
@@ -70,7 +76,7 @@ func setMessagesRequestDefaults(r *MessagesRequest) {
 }
 
 // RequestMessages sends a request for historic messages to a MailServer.
-func (api *PublicAPI) RequestMessages(_ context.Context, r MessagesRequest) (bool, error) {
+func (api *PublicAPI) RequestMessages(ctx context.Context, r MessagesRequest) (bool, error) {
 	api.log.Info("RequestMessages", "request", r)
 
 	setMessagesRequestDefaults(&r)
@@ -100,6 +106,10 @@ func (api *PublicAPI) RequestMessages(_ context.Context, r MessagesRequest) (boo
 		return false, err
 	}
 
+	if err := api.waitForPeer(ctx, node.Server(), mailServerNode); err != nil {
+		return false, err
+	}
+
 	if err := shh.RequestHistoricMessages(mailServerNode.ID[:], envelope); err != nil {
 		return false, err
 	}
@@ -107,6 +117,57 @@ func (api *PublicAPI) RequestMessages(_ context.Context, r MessagesRequest) (boo
 	return true, nil
 }
 
+// waitForPeer waits up to peerTimeout until MailServer peer is connected.
+// A signal is sent every PeerWaitProgressInterval while waiting.
+func (api *PublicAPI) waitForPeer(ctx context.Context, server *p2p.Server, peer *discover.Node) error {
+	if api.peerTimeout <= 0 || isPeerConnected(server, peer.ID) {
+		return nil
+	}
+
+	events := make(chan *p2p.PeerEvent, 16)
+	sub := server.SubscribeEvents(events)
+	defer sub.Unsubscribe()
+
+	// peer could connect before subscription
+	if isPeerConnected(server, peer.ID) {
+		return nil
+	}
+
+	api.log.Info("Waiting for MailServer peer", "peer", peer.ID, "timeout", api.peerTimeout)
+
+	start := time.Now()
+	timeout := time.NewTimer(api.peerTimeout)
+	defer timeout.Stop()
+	progress := time.NewTicker(PeerWaitProgressInterval)
+	defer progress.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			if event.Type == p2p.PeerEventTypeAdd && event.Peer == peer.ID {
+				return nil
+			}
+		case <-progress.C:
+			NotifyOnPeerWait(peer.ID, time.Since(start), api.peerTimeout)
+		case <-timeout.C:
+			return ErrMailServerPeerTimeout
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-sub.Err():
+			return err
+		}
+	}
+}
+
+func isPeerConnected(server *p2p.Server, id discover.NodeID) bool {
+	for _, p := range server.Peers() {
+		if p.ID() == id {
+			return true
+		}
+	}
+	return false
+}
+
 // makeEnvelop makes an envelop for a historic messages request.
 // Symmetric key is used to authenticate to MailServer.
 // PK is the current node ID.
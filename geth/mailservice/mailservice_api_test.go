@@ -21,7 +21,7 @@ func TestRequestMessagesDefaults(t *testing.T) {
 func TestRequestMessagesFailures(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	provider := NewMockServiceProvider(ctrl)
-	api := NewPublicAPI(provider)
+	api := NewPublicAPI(provider, 0)
 	shh := whisper.New(nil)
 	// Node is ephemeral (only in memory).
 	nodeA, nodeErr := node.New(&node.Config{NoUSB: true})
@@ -70,6 +70,31 @@ func TestRequestMessagesFailures(t *testing.T) {
 	require.False(t, result)
 }
 
+func TestRequestMessagesPeerTimeout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	provider := NewMockServiceProvider(ctrl)
+	api := NewPublicAPI(provider, 50*time.Millisecond)
+	shh := whisper.New(nil)
+	nodeA, nodeErr := node.New(&node.Config{NoUSB: true})
+	require.NoError(t, nodeErr)
+	require.NoError(t, nodeA.Start())
+	defer func() {
+		err := nodeA.Stop()
+		require.NoError(t, err)
+	}()
+
+	symKeyID, symKeyErr := shh.AddSymKeyFromPassword("some-pass")
+	require.NoError(t, symKeyErr)
+	provider.EXPECT().WhisperService().Return(shh, nil)
+	provider.EXPECT().GethNode().Return(nodeA, nil)
+	result, err := api.RequestMessages(context.TODO(), MessagesRequest{
+		MailServerPeer: "enode://b7e65e1bedc2499ee6cbd806945af5e7df0e59e4070c96821570bd581473eade24a489f5ec95d060c0db118c879403ab88d827d3766978f28708989d35474f87@[::]:51920",
+		SymKeyID:       symKeyID,
+	})
+	require.Equal(t, ErrMailServerPeerTimeout, err)
+	require.False(t, result)
+}
+
 func TestRequestMessagesSuccess(t *testing.T) {
 	// TODO(adam): next step would be to run a successful test, however,
 	// it requires to set up emepheral nodes that can discover each other
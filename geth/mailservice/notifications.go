@@ -0,0 +1,36 @@
+package mailservice
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/p2p/discover"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+const (
+	// EventMailServerPeerWaiting is triggered periodically while a request
+	// for historic messages waits for MailServer peer to connect
+	EventMailServerPeerWaiting = "mailserver.peer.waiting"
+)
+
+// PeerWaitProgressInterval is how often EventMailServerPeerWaiting is sent.
+var PeerWaitProgressInterval = time.Second
+
+// PeerWaitEvent is a signal sent while waiting for MailServer peer
+type PeerWaitEvent struct {
+	Peer    string `json:"peer"`
+	Elapsed int64  `json:"elapsed"` // milliseconds
+	Timeout int64  `json:"timeout"` // milliseconds
+}
+
+// NotifyOnPeerWait sends a notification that a request is waiting for MailServer peer
+func NotifyOnPeerWait(peer discover.NodeID, elapsed, timeout time.Duration) {
+	signal.Send(signal.Envelope{
+		Type: EventMailServerPeerWaiting,
+		Event: PeerWaitEvent{
+			Peer:    peer.String(),
+			Elapsed: int64(elapsed / time.Millisecond),
+			Timeout: int64(timeout / time.Millisecond),
+		},
+	})
+}
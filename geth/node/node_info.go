@@ -0,0 +1,49 @@
+package node
+
+import (
+	"fmt"
+	"sort"
+)
+
+// NodeInfo describes the running node, e.g. to share its enode with
+// somebody who adds it as a static peer.
+type NodeInfo struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Enode      string   `json:"enode"` // enode URL for adding this node from remote peers
+	ListenAddr string   `json:"listenAddr"`
+	Discovery  bool     `json:"discovery"` // whether peers are discovered
+	Protocols  []string `json:"protocols"` // served protocols with versions, e.g. "shh/6"
+	// Upstream is set if Ethereum requests are sent to the upstream. LES is not served
+	// by such node, so its protocols are limited to e.g. Whisper.
+	Upstream bool `json:"upstream"`
+}
+
+// NodeInfo returns information about the running node.
+func (n *StatusNode) NodeInfo() (NodeInfo, error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if err := n.isAvailable(); err != nil {
+		return NodeInfo{}, err
+	}
+	server := n.gethNode.Server()
+	if server == nil {
+		return NodeInfo{}, ErrNoRunningNode
+	}
+
+	self := server.Self()
+	info := NodeInfo{
+		ID:         self.ID.String(),
+		Name:       server.Name,
+		Enode:      self.String(),
+		ListenAddr: server.ListenAddr,
+		Discovery:  !server.NoDiscovery || server.DiscoveryV5,
+		Upstream:   n.config.UpstreamConfig.Enabled,
+	}
+	for _, proto := range server.Protocols {
+		info.Protocols = append(info.Protocols, fmt.Sprintf("%s/%d", proto.Name, proto.Version))
+	}
+	sort.Strings(info.Protocols)
+	return info, nil
+}
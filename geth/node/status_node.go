@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	ethereum "github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/les"
@@ -46,7 +47,8 @@ type StatusNode struct {
 	config   *params.NodeConfig // Status node configuration
 	gethNode *node.Node         // reference to Geth P2P stack/node
 
-	rpcClient *rpc.Client // reference to RPC client
+	rpcClient *rpc.Client   // reference to RPC client
+	sync      *syncReporter // reports sync progress of LES, if it is running
 	log       log.Logger
 }
 
@@ -79,7 +81,8 @@ func (n *StatusNode) start(config *params.NodeConfig) error {
 
 	// activate MailService required for Offline Inboxing
 	if err := ethNode.Register(func(_ *node.ServiceContext) (node.Service, error) {
-		return mailservice.New(n), nil
+		peerTimeout := time.Duration(config.WhisperConfig.MailServerPeerTimeout) * time.Second
+		return mailservice.New(n, peerTimeout), nil
 	}); err != nil {
 		return err
 	}
@@ -93,13 +96,36 @@ func (n *StatusNode) start(config *params.NodeConfig) error {
 	if err == nil {
 		n.rpcClient, err = rpc.NewClient(localRPCClient, n.config.UpstreamConfig)
 	}
+	if err == nil {
+		n.rpcClient.RejectUnknownMethods(n.config.UnknownRPCMethods == params.UnknownRPCMethodsReject)
+	}
 	if err != nil {
 		n.log.Error("Failed to create an RPC client", "error", err)
 		return RPCClientError(err)
 	}
+	n.startSyncReporter()
 	return nil
 }
 
+// startSyncReporter starts reporting sync progress of LES service.
+// Local private chain is never synced, so it isn't reported.
+// Lag below TransactionsConfig.MaxSyncLag isn't reported.
+func (n *StatusNode) startSyncReporter() {
+	if n.config.NetworkID == params.StatusChainNetworkID {
+		return
+	}
+	les, err := n.LightEthereumService()
+	if err != nil || les.Downloader() == nil {
+		return
+	}
+	maxLag := uint64(params.DefaultMaxSyncLag)
+	if n.config.TransactionsConfig != nil {
+		maxLag = n.config.TransactionsConfig.MaxSyncLag
+	}
+	n.sync = newSyncReporter(les.Downloader().Progress, maxLag)
+	n.sync.start()
+}
+
 // Stop will stop current StatusNode. A stopped node cannot be resumed.
 func (n *StatusNode) Stop() error {
 	n.mu.Lock()
@@ -112,6 +138,10 @@ func (n *StatusNode) stop() error {
 	if err := n.isAvailable(); err != nil {
 		return err
 	}
+	if n.sync != nil {
+		n.sync.stop()
+		n.sync = nil
+	}
 	if err := n.gethNode.Stop(); err != nil {
 		return err
 	}
@@ -349,16 +379,64 @@ const tickerResolution = time.Second
 // EnsureSync waits until blockchain synchronization
 // is complete and returns.
 func (n *StatusNode) EnsureSync(ctx context.Context) error {
+	return n.EnsureSyncWithin(ctx, 0)
+}
+
+// EnsureSyncWithin waits until blockchain of the node is no more
+// than tolerance blocks behind the highest known block and returns.
+func (n *StatusNode) EnsureSyncWithin(ctx context.Context, tolerance uint64) error {
 	// Don't wait for any blockchain sync for the
 	// local private chain as blocks are never mined.
 	if n.config.NetworkID == params.StatusChainNetworkID {
 		return nil
 	}
 
-	return n.ensureSync(ctx)
+	return n.ensureSync(ctx, tolerance)
+}
+
+// SyncGap returns how many blocks the node is behind the highest known block.
+func (n *StatusNode) SyncGap() (uint64, error) {
+	// local private chain is always in sync
+	if n.config.NetworkID == params.StatusChainNetworkID {
+		return 0, nil
+	}
+
+	les, err := n.LightEthereumService()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get LES service: %v", err)
+	}
+
+	downloader := les.Downloader()
+	if downloader == nil {
+		return 0, errors.New("LightEthereumService downloader is nil")
+	}
+
+	return syncGap(downloader.Progress()), nil
+}
+
+// IsSynced checks whether blockchain of the local node lags behind
+// the highest known block by no more than maxLag blocks.
+// Node which uses upstream is always considered synced.
+func (n *StatusNode) IsSynced(maxLag uint64) (bool, error) {
+	if n.config != nil && n.config.UpstreamConfig.Enabled {
+		return true, nil
+	}
+	gap, err := n.SyncGap()
+	if err != nil {
+		return false, err
+	}
+	return gap <= maxLag, nil
+}
+
+// syncGap returns number of blocks between current and highest blocks.
+func syncGap(progress ethereum.SyncProgress) uint64 {
+	if progress.CurrentBlock >= progress.HighestBlock {
+		return 0
+	}
+	return progress.HighestBlock - progress.CurrentBlock
 }
 
-func (n *StatusNode) ensureSync(ctx context.Context) error {
+func (n *StatusNode) ensureSync(ctx context.Context, tolerance uint64) error {
 	les, err := n.LightEthereumService()
 	if err != nil {
 		return fmt.Errorf("failed to get LES service: %v", err)
@@ -370,7 +448,7 @@ func (n *StatusNode) ensureSync(ctx context.Context) error {
 	}
 
 	progress := downloader.Progress()
-	if n.PeerCount() > 0 && progress.CurrentBlock >= progress.HighestBlock {
+	if n.PeerCount() > 0 && syncGap(progress) <= tolerance {
 		n.log.Debug("Synchronization completed", "current block", progress.CurrentBlock, "highest block", progress.HighestBlock)
 		return nil
 	}
@@ -390,12 +468,12 @@ func (n *StatusNode) ensureSync(ctx context.Context) error {
 				n.log.Debug("No established connections with any peers, continue waiting for a sync")
 				continue
 			}
-			if downloader.Synchronising() {
+			progress = downloader.Progress()
+			if downloader.Synchronising() && (tolerance == 0 || syncGap(progress) > tolerance) {
 				n.log.Debug("Synchronization is in progress")
 				continue
 			}
-			progress = downloader.Progress()
-			if progress.CurrentBlock >= progress.HighestBlock {
+			if syncGap(progress) <= tolerance {
 				n.log.Info("Synchronization completed", "current block", progress.CurrentBlock, "highest block", progress.HighestBlock)
 				return nil
 			}
@@ -0,0 +1,56 @@
+package node
+
+import (
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/t/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsSynced(t *testing.T) {
+	n := New()
+
+	// private chain doesn't need to be synced
+	n.config = &params.NodeConfig{NetworkID: params.StatusChainNetworkID}
+	synced, err := n.IsSynced(params.DefaultMaxSyncLag)
+	require.NoError(t, err)
+	require.True(t, synced)
+
+	// sync state of other networks requires LES service
+	n.config = &params.NodeConfig{NetworkID: params.RopstenNetworkID}
+	_, err = n.IsSynced(params.DefaultMaxSyncLag)
+	require.Error(t, err)
+
+	// upstream is always synced
+	n.config.UpstreamConfig.Enabled = true
+	synced, err = n.IsSynced(params.DefaultMaxSyncLag)
+	require.NoError(t, err)
+	require.True(t, synced)
+}
+
+func TestSyncGap(t *testing.T) {
+	require.Equal(t, uint64(0), syncGap(ethereum.SyncProgress{CurrentBlock: 10, HighestBlock: 10}))
+	require.Equal(t, uint64(0), syncGap(ethereum.SyncProgress{CurrentBlock: 11, HighestBlock: 10}))
+	require.Equal(t, uint64(5), syncGap(ethereum.SyncProgress{CurrentBlock: 5, HighestBlock: 10}))
+}
+
+func TestNodeInfo(t *testing.T) {
+	n := New()
+	_, err := n.NodeInfo()
+	require.Equal(t, ErrNoRunningNode, err)
+
+	config, err := utils.MakeTestNodeConfig(utils.GetNetworkID())
+	require.NoError(t, err)
+	require.NoError(t, n.Start(config))
+	defer func() {
+		require.NoError(t, n.Stop())
+	}()
+
+	info, err := n.NodeInfo()
+	require.NoError(t, err)
+	require.Contains(t, info.Enode, "enode://"+info.ID)
+	require.Contains(t, info.Protocols, "shh/6")
+	require.False(t, info.Upstream)
+}
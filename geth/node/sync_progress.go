@@ -0,0 +1,104 @@
+package node
+
+import (
+	"sync"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+// SyncProgressInterval is how often sync progress of the node is checked.
+const SyncProgressInterval = 2 * time.Second
+
+// syncReporter sends sync progress signals while the node syncs and
+// a final signal once it is synced. It resumes reporting if the node
+// falls behind by more than maxLag blocks.
+type syncReporter struct {
+	progress func() ethereum.SyncProgress
+	maxLag   uint64
+
+	syncing bool
+	last    ethereum.SyncProgress
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newSyncReporter creates reporter which is syncing initially.
+func newSyncReporter(progress func() ethereum.SyncProgress, maxLag uint64) *syncReporter {
+	return &syncReporter{
+		progress: progress,
+		maxLag:   maxLag,
+		syncing:  true,
+	}
+}
+
+func (r *syncReporter) start() {
+	r.quit = make(chan struct{})
+	r.wg.Add(1)
+	go r.loop()
+}
+
+func (r *syncReporter) stop() {
+	close(r.quit)
+	r.wg.Wait()
+}
+
+func (r *syncReporter) loop() {
+	defer r.wg.Done()
+	ticker := time.NewTicker(SyncProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.report(r.progress())
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// report sends a signal about the given progress if it changed.
+func (r *syncReporter) report(progress ethereum.SyncProgress) {
+	if !r.syncing {
+		if syncGap(progress) <= r.maxLag {
+			return
+		}
+		r.syncing = true
+	}
+	// highest block is unknown until the node is connected to peers
+	if progress.HighestBlock == 0 || progress == r.last {
+		return
+	}
+	r.last = progress
+
+	if syncGap(progress) == 0 {
+		r.syncing = false
+		signal.Send(signal.Envelope{Type: signal.EventSyncCompleted})
+		return
+	}
+	signal.Send(signal.Envelope{
+		Type: signal.EventSyncProgress,
+		Event: signal.SyncProgressEvent{
+			StartingBlock: progress.StartingBlock,
+			CurrentBlock:  progress.CurrentBlock,
+			HighestBlock:  progress.HighestBlock,
+			Percentage:    syncPercentage(progress),
+		},
+	})
+}
+
+// syncPercentage returns how much of blocks since the start of
+// synchronization are synced.
+func syncPercentage(progress ethereum.SyncProgress) float64 {
+	if progress.CurrentBlock >= progress.HighestBlock {
+		return 100
+	}
+	if progress.HighestBlock <= progress.StartingBlock || progress.CurrentBlock < progress.StartingBlock {
+		return 0
+	}
+	synced := progress.CurrentBlock - progress.StartingBlock
+	total := progress.HighestBlock - progress.StartingBlock
+	return float64(synced) / float64(total) * 100
+}
@@ -0,0 +1,43 @@
+package node
+
+import (
+	"encoding/json"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/status-im/status-go/geth/signal"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncReporter(t *testing.T) {
+	var events []string
+	signal.SetDefaultNodeNotificationHandler(func(jsonEvent string) {
+		var envelope signal.Envelope
+		require.NoError(t, json.Unmarshal([]byte(jsonEvent), &envelope))
+		events = append(events, envelope.Type)
+	})
+	defer signal.ResetDefaultNodeNotificationHandler()
+
+	r := newSyncReporter(nil, 10)
+	r.report(ethereum.SyncProgress{}) // no peers yet
+	r.report(ethereum.SyncProgress{StartingBlock: 0, CurrentBlock: 50, HighestBlock: 100})
+	r.report(ethereum.SyncProgress{StartingBlock: 0, CurrentBlock: 50, HighestBlock: 100}) // unchanged
+	r.report(ethereum.SyncProgress{StartingBlock: 0, CurrentBlock: 100, HighestBlock: 100})
+	require.Equal(t, []string{signal.EventSyncProgress, signal.EventSyncCompleted}, events)
+
+	// small lag is not reported
+	events = nil
+	r.report(ethereum.SyncProgress{StartingBlock: 100, CurrentBlock: 100, HighestBlock: 105})
+	require.Empty(t, events)
+
+	// reporting is resumed when node falls behind significantly
+	r.report(ethereum.SyncProgress{StartingBlock: 100, CurrentBlock: 100, HighestBlock: 120})
+	r.report(ethereum.SyncProgress{StartingBlock: 100, CurrentBlock: 120, HighestBlock: 120})
+	require.Equal(t, []string{signal.EventSyncProgress, signal.EventSyncCompleted}, events)
+}
+
+func TestSyncPercentage(t *testing.T) {
+	require.Equal(t, float64(50), syncPercentage(ethereum.SyncProgress{StartingBlock: 100, CurrentBlock: 150, HighestBlock: 200}))
+	require.Equal(t, float64(100), syncPercentage(ethereum.SyncProgress{CurrentBlock: 200, HighestBlock: 200}))
+	require.Equal(t, float64(0), syncPercentage(ethereum.SyncProgress{StartingBlock: 100, CurrentBlock: 50, HighestBlock: 200}))
+}
@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/status-im/status-go/static"
@@ -107,6 +109,14 @@ type WhisperConfig struct {
 	// TTL time to live for messages, in seconds
 	TTL int
 
+	// PoWTarget is a proof-of-work target of posted messages which don't specify it.
+	// It must not be below MinimumPoW, otherwise such messages are rejected.
+	PoWTarget float64
+
+	// MailServerPeerTimeout is how long, in seconds, a request for historic messages waits
+	// for MailServer peer to connect. Zero means that the request fails if it's not connected.
+	MailServerPeerTimeout int
+
 	// FirebaseConfig extra configuration for Firebase Cloud Messaging
 	FirebaseConfig *FirebaseConfig `json:"FirebaseConfig,"`
 }
@@ -183,6 +193,14 @@ func (c *ClusterConfig) String() string {
 // UpstreamRPCConfig
 // ----------
 
+// Values of NodeConfig.UnknownRPCMethods.
+const (
+	// UnknownRPCMethodsPassthrough forwards unknown methods to the local node.
+	UnknownRPCMethodsPassthrough = "passthrough"
+	// UnknownRPCMethodsReject fails unknown methods with "method not found" error.
+	UnknownRPCMethodsReject = "reject"
+)
+
 // UpstreamRPCConfig stores configuration for upstream rpc connection.
 type UpstreamRPCConfig struct {
 	// Enabled flag specifies whether feature is enabled
@@ -191,6 +209,176 @@ type UpstreamRPCConfig struct {
 	// URL sets the rpc upstream host address for communication with
 	// a non-local infura endpoint.
 	URL string
+
+	// AllowedURLs restricts upstreams the client may connect to. An entry is either
+	// a full URL or a host name, which allows any URL on that host. Empty list allows any URL.
+	AllowedURLs []string
+
+	// Headers are custom HTTP headers, e.g. an API key, which are attached
+	// to every request to the upstream. Values are never logged.
+	Headers map[string]string
+
+	// CacheEnabled flag specifies whether results of rarely changing
+	// read methods (e.g. eth_getCode) should be cached locally.
+	CacheEnabled bool
+
+	// CacheTTL overrides time to live, in seconds, of cached results per method.
+	// Zero TTL means that cached result never expires, negative TTL disables
+	// caching of the method, e.g. of eth_getBalance, which is cached by default.
+	CacheTTL map[string]int
+
+	// MaxConcurrentRequests limits the number of simultaneous requests to the upstream,
+	// excess requests are queued. Zero means that requests are not limited.
+	MaxConcurrentRequests int
+
+	// ConcurrentRequestsTimeout is how long, in seconds, a queued request may wait for
+	// the upstream before it fails. Zero means waiting until the request is canceled.
+	ConcurrentRequestsTimeout int
+
+	// CircuitBreakerFailures is a number of consecutive upstream failures after which
+	// calls to the upstream fail fast for CircuitBreakerCooldown. Zero disables the breaker.
+	CircuitBreakerFailures int
+
+	// CircuitBreakerCooldown is how long, in seconds, calls to the upstream fail fast
+	// before a single probe call is made.
+	CircuitBreakerCooldown int
+
+	// ChainURLs maps chain ids to upstream URLs which the app may switch to
+	// with wallet_switchEthereumChain.
+	ChainURLs map[uint64]string
+}
+
+// ----------
+// TransactionsConfig
+// ----------
+
+// TransactionsConfig holds configuration for signing and sending transactions.
+type TransactionsConfig struct {
+	// GasMultiplier is applied to the gas returned by eth_estimateGas before a transaction
+	// is signed. It is useful for transactions whose gas usage depends on the state at the
+	// time of mining. The result is capped at the latest block gas limit.
+	// Note that the multiplier only raises the gas limit: unused gas is refunded, so the fee
+	// of simple transfers, where estimation is exact, stays the same.
+	GasMultiplier float64 `validate:"gte=1"`
+
+	// LargeInputGasMultiplier is applied instead of GasMultiplier, if it is higher, to transactions
+	// with input of at least LargeInputSize bytes. Gas usage of complex contract interactions is
+	// more likely to exceed the estimation. Zero disables it.
+	LargeInputGasMultiplier float64 `validate:"gte=0"`
+
+	// LargeInputSize is the size, in bytes, of transaction input starting from which
+	// LargeInputGasMultiplier is applied.
+	LargeInputSize int `validate:"gte=0"`
+
+	// MaxInputSize is the maximum size, in bytes, of transaction input data
+	// (including contract creation code). Zero means that size is not limited.
+	MaxInputSize int `validate:"gte=0"`
+
+	// DisableReplayProtection makes transactions to be signed without EIP-155
+	// replay protection. It must be used only with private chains which do not
+	// support EIP-155.
+	DisableReplayProtection bool
+
+	// FallbackGasPrice is a gas price, in wei, used when transaction has no gas price
+	// set and upstream fails to suggest one. Zero means that there is no fallback.
+	FallbackGasPrice uint64
+
+	// NonceTooLowRetries is how many times a transaction rejected with "nonce too low"
+	// is re-signed with a refreshed pending nonce and sent again. Zero disables retries.
+	NonceTooLowRetries int `validate:"gte=0"`
+
+	// AllowSendDuringSync allows sending transactions while the local node is still
+	// syncing. Nonce and gas obtained from a node which is behind may be wrong.
+	AllowSendDuringSync bool
+
+	// MaxSyncLag is how many blocks the local node may lag behind the network
+	// for transactions to be sent. It isn't used if AllowSendDuringSync is set.
+	MaxSyncLag uint64
+
+	// AutoCompleteEnabled makes transactions sent by the selected account to one of
+	// AutoCompleteRecipients to be completed right away, with the key unlocked on login.
+	// Such transactions don't require confirmation and transaction.queued signal is not sent.
+	AutoCompleteEnabled bool
+
+	// AutoCompleteRecipients is a list of trusted recipients, used if AutoCompleteEnabled is set.
+	AutoCompleteRecipients []common.Address
+
+	// MinGasPrices defines, per network id, the minimum gas price in wei. Suggested or
+	// user-provided gas price below the minimum is raised to it, but never lowered.
+	MinGasPrices map[uint64]uint64
+
+	// DustThresholds defines, per network id, the value in wei below which a non-zero
+	// value of queued transaction is reported as dust. The transaction is not refused.
+	DustThresholds map[uint64]uint64
+
+	// MaxGasLimits defines, per network id, the maximum gas of a transaction. Transactions with
+	// higher gas set by the client are refused. Without a limit, the latest block gas limit is used.
+	MaxGasLimits map[uint64]uint64
+
+	// GasUsageCacheSize is a number of contract methods for which gas used by the latest
+	// call is remembered. Estimated gas of the next call is raised to it if lower. Zero disables it.
+	GasUsageCacheSize int `validate:"gte=0"`
+
+	// CompletionWorkers is a number of transactions of a batch which are completed concurrently,
+	// including decryption of the account key. Zero or one completes them one by one.
+	CompletionWorkers int `validate:"gte=0"`
+
+	// RefuseOnNonceGap makes new transactions to be refused while there is a nonce gap,
+	// i.e. the local nonce of the account is ahead of the pending one, as previously sent
+	// transactions are missing from the pending pool. A warning signal is sent regardless.
+	RefuseOnNonceGap bool
+
+	// AllowEmptyContractCode allows contract creation transactions without code.
+	// Such transactions are most likely misconstructed and refused by default.
+	AllowEmptyContractCode bool
+
+	// DiscardSuperseded makes queued transactions with an explicit nonce to be discarded
+	// once another transaction of the same account is sent with that nonce.
+	DiscardSuperseded bool
+
+	// UseSenderAccount makes transactions to be completed with the key of their sender
+	// if it is in the keystore, so that an account doesn't have to be selected first.
+	// By default, transactions are completed only with the selected account.
+	UseSenderAccount bool
+
+	// RejectWithoutAccount makes transactions to be refused right away if no account is selected
+	// and the key of the sender isn't available otherwise, i.e. it isn't unlocked for a session
+	// or, with UseSenderAccount, isn't in the keystore. By default, such transactions are queued
+	// and fail on completion.
+	RejectWithoutAccount bool
+
+	// ValueLimits defines, per network id, how much value can be sent without
+	// additional confirmation. Transactions over the limits are queued with
+	// a distinct signal, so that the app can require additional authentication.
+	ValueLimits map[uint64]ValueLimit
+
+	// MaxFiatFee is the maximum estimated fee of a transaction, in fiat currency of the price
+	// provider, which can be paid without additional confirmation. Transactions with higher fee
+	// are queued like transactions over ValueLimits. It is enforced only if the provider is set.
+	MaxFiatFee float64 `validate:"gte=0"`
+
+	// RejectEIP1559OnLegacyChains makes transactions with EIP-1559 fees to be refused on chains
+	// without base fee. By default, they are sent as legacy transactions.
+	RejectEIP1559OnLegacyChains bool
+}
+
+// ValueLimit limits value, in wei, of transactions.
+type ValueLimit struct {
+	// PerTransaction is a limit of a single transaction value. Nil means no limit.
+	PerTransaction *big.Int
+
+	// PerWindow is a limit of total value sent within Window, including a new
+	// transaction. Nil means no limit.
+	PerWindow *big.Int
+
+	// Window is a duration, in seconds, of PerWindow limit.
+	Window int
+}
+
+// String dumps config object as nicely indented JSON
+func (c *TransactionsConfig) String() string {
+	data, _ := json.MarshalIndent(c, "", "    ") // nolint: gas
+	return string(data)
 }
 
 // ----------
@@ -285,6 +473,11 @@ type NodeConfig struct {
 	// UpstreamConfig extra config for providing upstream infura server.
 	UpstreamConfig UpstreamRPCConfig `json:"UpstreamConfig"`
 
+	// UnknownRPCMethods defines how calls of methods, which aren't served by a registered handler,
+	// the upstream or the local node, are handled. Valid values are "passthrough" (default), which
+	// forwards them to the local node, and "reject", which fails them with "method not found" error.
+	UnknownRPCMethods string `validate:"omitempty,eq=passthrough|eq=reject"`
+
 	// ClusterConfigFile contains the file name of the cluster configuration. If
 	// empty the statical configuration data will be taken.
 	ClusterConfigFile string `json:"ClusterConfigFile"`
@@ -300,6 +493,9 @@ type NodeConfig struct {
 
 	// SwarmConfig extra configuration for Swarm and ENS
 	SwarmConfig *SwarmConfig `json:"SwarmConfig," validate:"structonly"`
+
+	// TransactionsConfig extra configuration for sending transactions
+	TransactionsConfig *TransactionsConfig `json:"TransactionsConfig," validate:"structonly"`
 }
 
 // NewNodeConfig creates new node configuration object
@@ -335,14 +531,23 @@ func NewNodeConfig(dataDir string, clstrCfgFile string, networkID uint64, devMod
 			DatabaseCache: DatabaseCache,
 		},
 		WhisperConfig: &WhisperConfig{
-			Enabled:    true,
-			MinimumPoW: WhisperMinimumPoW,
-			TTL:        WhisperTTL,
+			Enabled:               true,
+			MinimumPoW:            WhisperMinimumPoW,
+			TTL:                   WhisperTTL,
+			PoWTarget:             WhisperPoWTarget,
+			MailServerPeerTimeout: WhisperMailServerPeerTimeout,
 			FirebaseConfig: &FirebaseConfig{
 				NotificationTriggerURL: FirebaseNotificationTriggerURL,
 			},
 		},
 		SwarmConfig: &SwarmConfig{},
+		TransactionsConfig: &TransactionsConfig{
+			GasMultiplier:      DefaultGasMultiplier,
+			MaxInputSize:       DefaultMaxTxInputSize,
+			NonceTooLowRetries: DefaultNonceTooLowRetries,
+			MaxSyncLag:         DefaultMaxSyncLag,
+			MinGasPrices:       map[uint64]uint64{StatusChainNetworkID: DefaultStatusChainMinGasPrice},
+		},
 	}
 
 	// adjust dependent values
@@ -434,6 +639,10 @@ func (c *NodeConfig) Validate() error {
 		}
 	}
 
+	if err := validate.Struct(c.TransactionsConfig); err != nil {
+		return err
+	}
+
 	return nil
 }
 
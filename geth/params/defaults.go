@@ -48,6 +48,21 @@ const (
 	// DefaultGas default amount of gas used for transactions
 	DefaultGas = 180000
 
+	// DefaultGasMultiplier is applied to estimated gas by default (estimated gas is used as is)
+	DefaultGasMultiplier = 1.0
+
+	// DefaultMaxTxInputSize is the maximum size of transaction input data (128KB)
+	DefaultMaxTxInputSize = 128 * 1024
+
+	// DefaultNonceTooLowRetries is how many times transaction is resent after "nonce too low" error
+	DefaultNonceTooLowRetries = 1
+
+	// DefaultMaxSyncLag is how many blocks local node may lag behind the network to be considered synced
+	DefaultMaxSyncLag = 10
+
+	// DefaultStatusChainMinGasPrice is the minimum gas price on StatusChain (1 gwei)
+	DefaultStatusChainMinGasPrice = 1000000000
+
 	// DefaultFileDescriptorLimit is fd limit that database can use
 	DefaultFileDescriptorLimit = uint64(2048)
 
@@ -67,6 +82,9 @@ const (
 	// LogToStderr defines whether logged info should also be output to os.Stderr
 	LogToStderr = true
 
+	// GasPricesFile is a file where last gas prices chosen for accounts are stored, relative to DataDir
+	GasPricesFile = "gas_prices.json"
+
 	// WhisperDataDir is directory where Whisper data is stored, relative to DataDir
 	WhisperDataDir = "wnode"
 
@@ -76,6 +94,13 @@ const (
 	// WhisperTTL is time to live for messages, in seconds
 	WhisperTTL = 120
 
+	// WhisperPoWTarget is proof-of-work target of posted messages
+	WhisperPoWTarget = WhisperMinimumPoW
+
+	// WhisperMailServerPeerTimeout is how long, in seconds, a request to MailServer
+	// waits for the MailServer peer to connect
+	WhisperMailServerPeerTimeout = 10
+
 	// FirebaseNotificationTriggerURL is URL where FCM notification requests are sent to
 	FirebaseNotificationTriggerURL = "https://fcm.googleapis.com/fcm/send"
 
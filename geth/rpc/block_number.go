@@ -0,0 +1,107 @@
+package rpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BlockNumberTTL is how long the polled number of the latest block is reused.
+const BlockNumberTTL = time.Second
+
+// headTracker keeps the number of the latest block.
+type headTracker struct {
+	mu         sync.RWMutex // guards fields below
+	number     uint64
+	expires    time.Time
+	subscribed bool // number is kept fresh by newHeads subscription
+}
+
+func (h *headTracker) get() (uint64, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.subscribed && h.number > 0 {
+		return h.number, true
+	}
+	return h.number, time.Now().Before(h.expires)
+}
+
+// set updates polled number of the latest block.
+func (h *headTracker) set(number uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.number = number
+	h.expires = time.Now().Add(BlockNumberTTL)
+}
+
+// reset forgets polled number of the latest block,
+// e.g. after the upstream was switched.
+func (h *headTracker) reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.expires = time.Time{}
+}
+
+// setSubscribed updates number of the latest block received by subscription.
+// Value is reset to polling if subscribed is false.
+func (h *headTracker) setSubscribed(number uint64, subscribed bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if subscribed {
+		h.number = number
+	}
+	h.subscribed = subscribed
+}
+
+// BlockNumber returns the number of the latest block. When calls are served by the
+// local node, the number is kept fresh by newHeads subscription. Otherwise, result
+// of eth_blockNumber is reused for BlockNumberTTL.
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	c.headOnce.Do(c.subscribeNewHeads)
+
+	if number, ok := c.head.get(); ok {
+		return number, nil
+	}
+
+	var number hexutil.Uint64
+	if err := c.CallContext(ctx, &number, "eth_blockNumber"); err != nil {
+		return 0, err
+	}
+	c.head.set(uint64(number))
+	return uint64(number), nil
+}
+
+// subscribeNewHeads subscribes to new blocks of the local node, if it is used.
+func (c *Client) subscribeNewHeads() {
+	if c.local == nil || c.router.routeRemote("eth_blockNumber") {
+		return
+	}
+
+	headers := make(chan *types.Header)
+	sub, err := c.local.EthSubscribe(context.Background(), headers, "newHeads")
+	if err != nil {
+		c.log.Warn("failed to subscribe to new heads, block number is polled", "err", err)
+		return
+	}
+
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case header := <-headers:
+				c.head.setSubscribed(header.Number.Uint64(), true)
+			case err := <-sub.Err():
+				c.log.Warn("new heads subscription is closed, block number is polled", "err", err)
+				c.head.setSubscribed(0, false)
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,50 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockNumberPolling(t *testing.T) {
+	c, err := NewClient(nil, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+
+	var calls int
+	c.RegisterHandler("eth_blockNumber", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		calls++
+		return hexutil.Uint64(16), nil
+	})
+
+	for i := 0; i < 3; i++ {
+		number, err := c.BlockNumber(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, uint64(16), number)
+	}
+	require.Equal(t, 1, calls, "block number expected to be reused")
+
+	c.head.expires = time.Now().Add(-time.Second)
+	_, err = c.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+}
+
+func TestHeadTrackerSubscription(t *testing.T) {
+	var h headTracker
+	_, ok := h.get()
+	require.False(t, ok)
+
+	h.setSubscribed(20, true)
+	number, ok := h.get()
+	require.True(t, ok)
+	require.Equal(t, uint64(20), number)
+
+	// once subscription is closed, value expires as a polled one
+	h.setSubscribed(0, false)
+	_, ok = h.get()
+	require.False(t, ok)
+}
@@ -0,0 +1,153 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/status-im/status-go/geth/signal"
+)
+
+// ErrUpstreamUnavailable is returned without calling the upstream
+// when it failed too many times in a row.
+var ErrUpstreamUnavailable = errors.New("upstream is unavailable")
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// breaker is a circuit breaker around upstream calls. After maxFailures
+// consecutive failures it opens and fast-fails all calls for cooldown,
+// then a single probe call is allowed. The breaker closes if the probe
+// succeeds and opens again otherwise.
+type breaker struct {
+	mu          sync.Mutex
+	state       breakerState
+	failures    int
+	openedAt    time.Time
+	maxFailures int
+	cooldown    time.Duration
+
+	onChange func(open bool)  // called on open/close transitions
+	now      func() time.Time // to be replaced in tests
+}
+
+// newBreaker creates breaker which opens after maxFailures
+// consecutive failures.
+func newBreaker(maxFailures int, cooldown time.Duration, onChange func(open bool)) *breaker {
+	return &breaker{
+		maxFailures: maxFailures,
+		cooldown:    cooldown,
+		onChange:    onChange,
+		now:         time.Now,
+	}
+}
+
+// allow returns ErrUpstreamUnavailable if a call must not be made.
+// Every allowed call must be reported with done.
+func (b *breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return ErrUpstreamUnavailable
+		}
+		b.state = breakerHalfOpen
+		return nil
+	case breakerHalfOpen:
+		// probe is in progress
+		return ErrUpstreamUnavailable
+	}
+	return nil
+}
+
+// done reports a result of the call allowed by allow.
+func (b *breaker) done(ctx context.Context, err error) {
+	switch {
+	case isInconclusive(ctx, err):
+		b.skip()
+	case isUpstreamFailure(err):
+		b.fail()
+	default:
+		b.succeed()
+	}
+}
+
+// skip handles a call which says nothing about the upstream. If it was
+// a probe, the breaker is opened again, so that the next call is a probe.
+func (b *breaker) skip() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+	}
+}
+
+func (b *breaker) succeed() {
+	b.mu.Lock()
+	closed := b.state != breakerClosed
+	b.state = breakerClosed
+	b.failures = 0
+	b.mu.Unlock()
+
+	if closed && b.onChange != nil {
+		b.onChange(false)
+	}
+}
+
+func (b *breaker) fail() {
+	b.mu.Lock()
+	b.failures++
+	opened := false
+	switch {
+	case b.state == breakerHalfOpen:
+		// failed probe, restart cooldown without notifying again
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	case b.state == breakerClosed && b.failures >= b.maxFailures:
+		b.state = breakerOpen
+		b.openedAt = b.now()
+		opened = true
+	}
+	b.mu.Unlock()
+
+	if opened && b.onChange != nil {
+		b.onChange(true)
+	}
+}
+
+// isInconclusive returns true if the call was canceled or rejected by
+// the limiter, so it says nothing about the upstream.
+func isInconclusive(ctx context.Context, err error) bool {
+	return err == ErrUpstreamBusy || ctx.Err() != nil
+}
+
+// isUpstreamFailure returns true if err means that upstream is not reachable.
+// JSON-RPC errors are valid responses, so they are not considered failures.
+func isUpstreamFailure(err error) bool {
+	if err == nil || err == gethrpc.ErrNoResult {
+		return false
+	}
+	if _, ok := err.(gethrpc.Error); ok {
+		return false
+	}
+	return true
+}
+
+// notifyUpstreamAvailability sends a signal when the breaker opens or closes.
+func notifyUpstreamAvailability(open bool) {
+	if open {
+		signal.Send(signal.Envelope{Type: signal.EventUpstreamUnavailable})
+		return
+	}
+	signal.Send(signal.Envelope{Type: signal.EventUpstreamAvailable})
+}
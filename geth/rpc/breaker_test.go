@@ -0,0 +1,93 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type rpcError struct{}
+
+func (rpcError) Error() string  { return "execution reverted" }
+func (rpcError) ErrorCode() int { return -32000 }
+
+func TestBreaker(t *testing.T) {
+	var transitions []bool
+	b := newBreaker(2, time.Minute, func(open bool) {
+		transitions = append(transitions, open)
+	})
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	failure := errors.New("connection refused")
+
+	// JSON-RPC errors are not failures
+	require.NoError(t, b.allow())
+	b.done(ctx, rpcError{})
+	require.NoError(t, b.allow())
+	b.done(ctx, failure)
+	require.NoError(t, b.allow())
+	b.done(ctx, nil)
+
+	// opens after consecutive failures
+	for i := 0; i < 2; i++ {
+		require.NoError(t, b.allow())
+		b.done(ctx, failure)
+	}
+	require.Equal(t, ErrUpstreamUnavailable, b.allow())
+	require.Equal(t, []bool{true}, transitions)
+
+	// a single probe is allowed after cooldown, failed probe opens breaker again
+	now = now.Add(time.Minute)
+	require.NoError(t, b.allow())
+	require.Equal(t, ErrUpstreamUnavailable, b.allow())
+	b.done(ctx, failure)
+	require.Equal(t, ErrUpstreamUnavailable, b.allow())
+	require.Equal(t, []bool{true}, transitions)
+
+	// successful probe closes breaker
+	now = now.Add(time.Minute)
+	require.NoError(t, b.allow())
+	b.done(ctx, nil)
+	require.NoError(t, b.allow())
+	require.Equal(t, []bool{true, false}, transitions)
+}
+
+func TestBreakerInconclusiveProbe(t *testing.T) {
+	var transitions []bool
+	b := newBreaker(1, time.Minute, func(open bool) {
+		transitions = append(transitions, open)
+	})
+	now := time.Now()
+	b.now = func() time.Time { return now }
+
+	require.NoError(t, b.allow())
+	b.done(context.Background(), errors.New("connection refused"))
+	require.Equal(t, ErrUpstreamUnavailable, b.allow())
+
+	// canceled and busy probes don't close breaker, the next call is a probe again
+	now = now.Add(time.Minute)
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, probe := range []struct {
+		ctx context.Context
+		err error
+	}{
+		{canceled, context.Canceled},
+		{context.Background(), ErrUpstreamBusy},
+	} {
+		require.NoError(t, b.allow())
+		b.done(probe.ctx, probe.err)
+		require.Equal(t, breakerOpen, b.state)
+	}
+	require.Equal(t, []bool{true}, transitions)
+
+	require.NoError(t, b.allow())
+	b.done(context.Background(), nil)
+	require.Equal(t, breakerClosed, b.state)
+	require.Equal(t, []bool{true, false}, transitions)
+}
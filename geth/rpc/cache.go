@@ -0,0 +1,160 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL defines methods which results are cached by default
+// and time to live of cached results. Zero TTL means that result never expires.
+// Caching of a method is disabled with a negative TTL in config, see cacheTTLFromConfig.
+var DefaultCacheTTL = map[string]time.Duration{
+	"eth_getCode":    0,
+	"eth_chainId":    0,
+	"net_version":    0,
+	"eth_getBalance": 5 * time.Second,
+}
+
+// defaultCacheSize is the maximum number of cached results.
+const defaultCacheSize = 1000
+
+type cacheEntry struct {
+	result  json.RawMessage
+	expires time.Time // zero value means that entry never expires
+	seq     uint64    // order in which entries are set, the oldest is evicted first
+}
+
+// cache stores results of JSON-RPC calls keyed by method and params.
+type cache struct {
+	mu      sync.RWMutex // guards entries
+	entries map[string]cacheEntry
+	ttl     map[string]time.Duration
+	size    int    // maximum number of entries
+	seq     uint64 // seq of the last set entry
+}
+
+// newCache creates cache for methods with the given TTLs,
+// which holds at most size results.
+func newCache(ttl map[string]time.Duration, size int) *cache {
+	return &cache{
+		entries: make(map[string]cacheEntry),
+		ttl:     ttl,
+		size:    size,
+	}
+}
+
+// middleware returns Middleware which serves cacheable methods from the cache.
+func (c *cache) middleware(next CallHandler) CallHandler {
+	return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+		ttl, ok := c.ttl[method]
+		if !ok {
+			return next(ctx, result, method, args...)
+		}
+
+		key, err := cacheKey(method, args)
+		if err != nil {
+			return next(ctx, result, method, args...)
+		}
+
+		raw, ok := c.get(key)
+		if !ok {
+			if err := next(ctx, &raw, method, args...); err != nil {
+				return err
+			}
+			c.set(key, raw, ttl)
+		}
+
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(raw, result)
+	}
+}
+
+func (c *cache) get(key string) (json.RawMessage, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		return nil, false
+	}
+	return entry.result, true
+}
+
+func (c *cache) set(key string, result json.RawMessage, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.size {
+		c.evict()
+	}
+
+	c.seq++
+	entry := cacheEntry{result: result, seq: c.seq}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	c.entries[key] = entry
+}
+
+// evict removes expired entries. If the cache is still full, the oldest
+// entries are removed to make room for a new one. Must be called with
+// mu locked.
+func (c *cache) evict() {
+	now := time.Now()
+	for key, entry := range c.entries {
+		if !entry.expires.IsZero() && now.After(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+	for len(c.entries) >= c.size && len(c.entries) > 0 {
+		var oldest string
+		for key, entry := range c.entries {
+			if oldest == "" || entry.seq < c.entries[oldest].seq {
+				oldest = key
+			}
+		}
+		delete(c.entries, oldest)
+	}
+}
+
+// clear removes all cached results.
+func (c *cache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]cacheEntry)
+}
+
+// cacheKey builds a key out of method name and its params.
+func cacheKey(method string, args []interface{}) (string, error) {
+	params, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%s", method, params), nil
+}
+
+// cacheTTLFromConfig merges default TTLs with ones from config (in seconds).
+// Negative TTL in config disables caching of the method.
+func cacheTTLFromConfig(config map[string]int) map[string]time.Duration {
+	ttl := make(map[string]time.Duration, len(DefaultCacheTTL)+len(config))
+	for method, d := range DefaultCacheTTL {
+		ttl[method] = d
+	}
+	for method, seconds := range config {
+		if seconds < 0 {
+			delete(ttl, method)
+			continue
+		}
+		ttl[method] = time.Duration(seconds) * time.Second
+	}
+	return ttl
+}
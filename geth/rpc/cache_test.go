@@ -0,0 +1,115 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheTTLFromConfig(t *testing.T) {
+	ttl := cacheTTLFromConfig(map[string]int{
+		"eth_getBalance": 10,
+		"eth_call":       1,
+		"eth_chainId":    -1,
+	})
+	require.Equal(t, time.Duration(0), ttl["eth_getCode"])
+	require.Equal(t, 10*time.Second, ttl["eth_getBalance"])
+	require.Equal(t, time.Second, ttl["eth_call"])
+	_, ok := ttl["eth_chainId"]
+	require.False(t, ok, "negative TTL disables caching")
+}
+
+func TestCachedCalls(t *testing.T) {
+	c, err := NewClient(nil, params.UpstreamRPCConfig{
+		CacheEnabled: true,
+	})
+	require.NoError(t, err)
+
+	var calls int
+	handler := func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		calls++
+		return "0x60606040", nil
+	}
+	c.RegisterHandler("eth_getCode", handler)
+	c.RegisterHandler("eth_getStorageAt", handler)
+
+	var code string
+	for i := 0; i < 3; i++ {
+		require.NoError(t, c.Call(&code, "eth_getCode", "0xb60e8dd61c5d32be8058bb8eb970870f07233155", "latest"))
+		require.Equal(t, "0x60606040", code)
+	}
+	require.Equal(t, 1, calls, "results expected to be cached")
+
+	// different params are cached separately
+	require.NoError(t, c.Call(&code, "eth_getCode", "0xd46e8dd67c5d32be8058bb8eb970870f07244567", "latest"))
+	require.Equal(t, 2, calls)
+
+	// methods that are not cacheable always hit the handler
+	require.NoError(t, c.Call(&code, "eth_getStorageAt", "0xb60e8dd61c5d32be8058bb8eb970870f07233155", "0x0", "latest"))
+	require.NoError(t, c.Call(&code, "eth_getStorageAt", "0xb60e8dd61c5d32be8058bb8eb970870f07233155", "0x0", "latest"))
+	require.Equal(t, 4, calls)
+
+	c.ClearCache()
+	require.NoError(t, c.Call(&code, "eth_getCode", "0xb60e8dd61c5d32be8058bb8eb970870f07233155", "latest"))
+	require.Equal(t, 5, calls)
+}
+
+func TestCacheExpiration(t *testing.T) {
+	c := newCache(map[string]time.Duration{"eth_getBalance": time.Millisecond}, defaultCacheSize)
+	c.set("key", []byte(`"0x1"`), time.Millisecond)
+	_, ok := c.get("key")
+	require.True(t, ok)
+	time.Sleep(2 * time.Millisecond)
+	_, ok = c.get("key")
+	require.False(t, ok)
+}
+
+func TestCacheEviction(t *testing.T) {
+	c := newCache(map[string]time.Duration{"eth_getBalance": time.Millisecond}, 2)
+	c.set("expired", []byte(`"0x1"`), time.Millisecond)
+	c.set("permanent", []byte(`"0x2"`), 0)
+	time.Sleep(2 * time.Millisecond)
+
+	// expired entries are removed first
+	c.set("new", []byte(`"0x3"`), 0)
+	require.Len(t, c.entries, 2)
+	_, ok := c.get("permanent")
+	require.True(t, ok)
+
+	// cache never grows above its size, the oldest entry is removed
+	c.set("another", []byte(`"0x4"`), 0)
+	require.Len(t, c.entries, 2)
+	_, ok = c.get("another")
+	require.True(t, ok)
+	_, ok = c.get("new")
+	require.True(t, ok)
+	_, ok = c.get("permanent")
+	require.False(t, ok)
+}
+
+func TestCachedCallsPassMiddlewares(t *testing.T) {
+	c, err := NewClient(nil, params.UpstreamRPCConfig{
+		CacheEnabled: true,
+	})
+	require.NoError(t, err)
+	c.RegisterHandler("eth_getCode", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		return "0x60606040", nil
+	})
+
+	var seen int
+	c.Use(func(next CallHandler) CallHandler {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			seen++
+			return next(ctx, result, method, args...)
+		}
+	})
+
+	var code string
+	for i := 0; i < 3; i++ {
+		require.NoError(t, c.Call(&code, "eth_getCode", "0xb60e8dd61c5d32be8058bb8eb970870f07233155", "latest"))
+	}
+	require.Equal(t, 3, seen, "cached results must not bypass middlewares")
+}
@@ -18,9 +18,11 @@ type Call struct {
 var (
 	ErrInvalidFromAddress = errors.New("Failed to parse From Address")
 	ErrInvalidToAddress   = errors.New("Failed to parse To Address")
+	ErrMissingToAddress   = errors.New("To Address is missing")
 )
 
 // ParseFromAddress returns the address associated with the Call.
+// Address which is not a valid hex address is refused.
 func (c Call) ParseFromAddress() (gethcommon.Address, error) {
 	params, ok := c.Params[0].(map[string]interface{})
 	if !ok {
@@ -28,7 +30,7 @@ func (c Call) ParseFromAddress() (gethcommon.Address, error) {
 	}
 
 	from, ok := params["from"].(string)
-	if !ok {
+	if !ok || !gethcommon.IsHexAddress(from) {
 		return gethcommon.HexToAddress("0x"), ErrInvalidFromAddress
 	}
 
@@ -36,14 +38,20 @@ func (c Call) ParseFromAddress() (gethcommon.Address, error) {
 }
 
 // ParseToAddress returns the gethcommon.Address associated with the call.
+// ErrMissingToAddress is returned if it is not set, e.g. for contract creation,
+// and ErrInvalidToAddress if it is not a valid hex address.
 func (c Call) ParseToAddress() (gethcommon.Address, error) {
 	params, ok := c.Params[0].(map[string]interface{})
 	if !ok {
 		return gethcommon.HexToAddress("0x"), ErrInvalidToAddress
 	}
 
-	to, ok := params["to"].(string)
-	if !ok {
+	value, ok := params["to"]
+	if !ok || value == nil {
+		return gethcommon.HexToAddress("0x"), ErrMissingToAddress
+	}
+	to, ok := value.(string)
+	if !ok || !gethcommon.IsHexAddress(to) {
 		return gethcommon.HexToAddress("0x"), ErrInvalidToAddress
 	}
 
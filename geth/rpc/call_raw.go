@@ -3,14 +3,21 @@ package rpc
 import (
 	"context"
 	"encoding/json"
+	"sync"
 
 	gethrpc "github.com/ethereum/go-ethereum/rpc"
 )
 
-const (
-	jsonrpcVersion        = "2.0"
-	errInvalidMessageCode = -32700 // from go-ethereum/rpc/errors.go
-)
+// JSONRPCVersion is the version of JSON-RPC messages.
+const JSONRPCVersion = "2.0"
+
+const errInvalidMessageCode = -32700 // from go-ethereum/rpc/errors.go
+
+// txMethods are methods which queue a transaction and block until
+// it is completed or discarded.
+var txMethods = map[string]bool{
+	"eth_sendTransaction": true,
+}
 
 // for JSON-RPC responses obtained via CallRaw(), we have no way
 // to know ID field from actual response. web3.js (primary and
@@ -92,11 +99,24 @@ func (c *Client) callBatchMethods(ctx context.Context, msgs json.RawMessage) str
 	// run all methods sequentially, this seems to be main
 	// objective to use batched requests.
 	// See: https://github.com/ethereum/wiki/wiki/JavaScript-API#batch-requests
+	// Transactions are queued concurrently though, each with its own sign
+	// request, as otherwise the next one is queued only after the previous
+	// one is completed.
 	responses := make([]json.RawMessage, len(requests))
+	var wg sync.WaitGroup
 	for i := range requests {
+		if msg, err := unmarshalMessage(requests[i]); err == nil && txMethods[msg.Method] {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				responses[i] = json.RawMessage(c.callSingleMethod(ctx, requests[i]))
+			}(i)
+			continue
+		}
 		resp := c.callSingleMethod(ctx, requests[i])
 		responses[i] = json.RawMessage(resp)
 	}
+	wg.Wait()
 
 	data, err := json.Marshal(responses)
 	if err != nil {
@@ -170,7 +190,7 @@ func newSuccessResponse(result json.RawMessage, id json.RawMessage) string {
 	msg := &jsonrpcSuccessfulResponse{
 		jsonrpcMessage: jsonrpcMessage{
 			ID:      id,
-			Version: jsonrpcVersion,
+			Version: JSONRPCVersion,
 		},
 		Result: result,
 	}
@@ -190,13 +210,16 @@ func newErrorResponse(code int, err error, id json.RawMessage) string {
 	errMsg := &jsonrpcErrorResponse{
 		jsonrpcMessage: jsonrpcMessage{
 			ID:      id,
-			Version: jsonrpcVersion,
+			Version: JSONRPCVersion,
 		},
 		Error: jsonError{
 			Code:    code,
 			Message: err.Error(),
 		},
 	}
+	if e, ok := err.(*Error); ok {
+		errMsg.Error.Data = e.Data
+	}
 
 	data, _ := json.Marshal(errMsg)
 	return string(data)
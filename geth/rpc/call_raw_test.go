@@ -1,10 +1,14 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/status-im/status-go/geth/params"
 	"github.com/stretchr/testify/require"
 )
 
@@ -37,6 +41,14 @@ func TestNewErrorResponse(t *testing.T) {
 	require.Equal(t, expected, got)
 }
 
+func TestNewErrorResponseWithData(t *testing.T) {
+	err := &Error{Code: 3, Message: "execution reverted", Data: "0x08c379a0"}
+	got := newErrorResponse(err.ErrorCode(), err, json.RawMessage(`42`))
+
+	expected := `{"jsonrpc":"2.0","id":42,"error":{"code":3,"message":"execution reverted","data":"0x08c379a0"}}`
+	require.Equal(t, expected, got)
+}
+
 func TestUnmarshalMessage(t *testing.T) {
 	body := json.RawMessage(`{"jsonrpc": "2.0", "method": "subtract", "params": {"subtrahend": 23, "minuend": 42}}`)
 	got, err := unmarshalMessage(body)
@@ -146,3 +158,36 @@ func TestIsBatch(t *testing.T) {
 		})
 	}
 }
+
+func TestCallRawBatchQueuesTransactions(t *testing.T) {
+	c, err := NewClient(nil, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+
+	// each transaction is completed only when both of them are queued
+	var queued sync.WaitGroup
+	queued.Add(2)
+	allQueued := make(chan struct{})
+	go func() {
+		queued.Wait()
+		close(allQueued)
+	}()
+	c.RegisterHandler("eth_sendTransaction", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		queued.Done()
+		select {
+		case <-allQueued:
+			return args[0], nil
+		case <-time.After(time.Second):
+			return nil, errors.New("transactions are not queued concurrently")
+		}
+	})
+	c.RegisterHandler("test_echo", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		return args[0], nil
+	})
+
+	raw := c.CallRaw(`[
+		{"jsonrpc": "2.0", "id": 1, "method": "eth_sendTransaction", "params": ["first"]},
+		{"jsonrpc": "2.0", "id": 2, "method": "test_echo", "params": ["echo"]},
+		{"jsonrpc": "2.0", "id": 3, "method": "eth_sendTransaction", "params": ["second"]}
+	]`)
+	require.Equal(t, `[{"jsonrpc":"2.0","id":1,"result":"first"},{"jsonrpc":"2.0","id":2,"result":"echo"},{"jsonrpc":"2.0","id":3,"result":"second"}]`, raw)
+}
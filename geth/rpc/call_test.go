@@ -0,0 +1,31 @@
+package rpc
+
+import (
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddresses(t *testing.T) {
+	valid := "0xbF164ca341326a03b547c05B343b2E21eFAe24b9"
+	call := Call{Params: []interface{}{map[string]interface{}{"from": valid, "to": valid}}}
+	from, err := call.ParseFromAddress()
+	require.NoError(t, err)
+	require.Equal(t, gethcommon.HexToAddress(valid), from)
+	to, err := call.ParseToAddress()
+	require.NoError(t, err)
+	require.Equal(t, gethcommon.HexToAddress(valid), to)
+
+	for _, invalid := range []interface{}{"0xbF164ca341326a03", "bF164ca341326a03b547c05B343b2E21eFAe24b9zz", "0xgF164ca341326a03b547c05B343b2E21eFAe24b9", 1} {
+		call = Call{Params: []interface{}{map[string]interface{}{"from": invalid, "to": invalid}}}
+		_, err = call.ParseFromAddress()
+		require.Equal(t, ErrInvalidFromAddress, err, "address: %v", invalid)
+		_, err = call.ParseToAddress()
+		require.Equal(t, ErrInvalidToAddress, err, "address: %v", invalid)
+	}
+
+	call = Call{Params: []interface{}{map[string]interface{}{"from": valid}}}
+	_, err = call.ParseToAddress()
+	require.Equal(t, ErrMissingToAddress, err)
+}
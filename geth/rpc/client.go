@@ -7,31 +7,88 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/status-im/status-go/geth/params"
 
 	gethrpc "github.com/ethereum/go-ethereum/rpc"
 )
 
+// ErrUpstreamDisabled is returned when upstream is switched on a client without upstream.
+var ErrUpstreamDisabled = errors.New("upstream is not enabled")
+
+// ErrUpstreamNotAllowed is returned when upstream URL is not in the list of allowed upstreams.
+var ErrUpstreamNotAllowed = errors.New("upstream is not allowed")
+
+// ErrUpstreamChainMismatch is returned when upstream serves another chain than expected.
+var ErrUpstreamChainMismatch = errors.New("upstream serves another chain")
+
+// ReadOnlyMethods defines methods without side effects, which locally registered
+// handlers are left running in background if the call's context is canceled.
+var ReadOnlyMethods = map[string]bool{
+	"eth_accounts":              true,
+	"eth_blockNumber":           true,
+	"eth_call":                  true,
+	"eth_chainId":               true,
+	"eth_estimateGas":           true,
+	"eth_gasPrice":              true,
+	"eth_getBalance":            true,
+	"eth_getBlockByNumber":      true,
+	"eth_getCode":               true,
+	"eth_getLogs":               true,
+	"eth_getTransactionByHash":  true,
+	"eth_getTransactionCount":   true,
+	"eth_getTransactionReceipt": true,
+	"net_version":               true,
+}
+
 // Handler defines handler for RPC methods.
 type Handler func(context.Context, ...interface{}) (interface{}, error)
 
+// CallHandler performs a JSON-RPC call and unmarshals response into result.
+// It has the same semantics as Client.CallContext.
+type CallHandler func(ctx context.Context, result interface{}, method string, args ...interface{}) error
+
+// Middleware wraps CallHandler in order to intercept JSON-RPC calls.
+// It can inspect, rewrite or short-circuit a call before it is routed.
+type Middleware func(next CallHandler) CallHandler
+
 // Client represents RPC client with custom routing
 // scheme. It automatically decides where RPC call
 // goes - Upstream or Local node.
 type Client struct {
 	upstreamEnabled bool
 	upstreamURL     string
+	upstreamHeaders map[string]string // attached to every upstream request
+	allowedURLs     []string          // empty allows any upstream
 
-	local    *gethrpc.Client
-	upstream *gethrpc.Client
+	local      *gethrpc.Client
+	upstreamMx sync.RWMutex // mx guards upstream and upstreamURL
+	upstream   *gethrpc.Client
 
 	router *router
 
 	handlersMx sync.RWMutex       // mx guards handlers
 	handlers   map[string]Handler // locally registered handlers
-	log        log.Logger
+
+	unknownMx     sync.RWMutex    // mx guards rejectUnknown and modules
+	rejectUnknown bool            // fail methods which aren't served by anyone
+	modules       map[string]bool // API modules of the local node, queried on first use
+
+	middlewaresMx sync.RWMutex // mx guards middlewares
+	middlewares   []Middleware // applied to every call, in order of registration
+
+	cache     *cache     // optional cache of read-only calls
+	coalescer *coalescer // shares identical in-flight calls
+	limiter   *limiter   // optional limit of concurrent upstream calls
+	breaker   *breaker   // optional circuit breaker of upstream calls
+
+	headOnce sync.Once // to subscribe to new heads on first use
+	head     headTracker
+
+	log log.Logger
 }
 
 // NewClient initializes Client and tries to connect to both,
@@ -51,7 +108,12 @@ func NewClient(client *gethrpc.Client, upstream params.UpstreamRPCConfig) (*Clie
 	if upstream.Enabled {
 		c.upstreamEnabled = upstream.Enabled
 		c.upstreamURL = upstream.URL
-		c.upstream, err = gethrpc.Dial(c.upstreamURL)
+		c.upstreamHeaders = upstream.Headers
+		c.allowedURLs = upstream.AllowedURLs
+		if !isUpstreamAllowed(c.upstreamURL, c.allowedURLs) {
+			return nil, ErrUpstreamNotAllowed
+		}
+		c.upstream, err = c.dialUpstream(c.upstreamURL)
 		if err != nil {
 			return nil, fmt.Errorf("dial upstream server: %s", err)
 		}
@@ -59,6 +121,21 @@ func NewClient(client *gethrpc.Client, upstream params.UpstreamRPCConfig) (*Clie
 
 	c.router = newRouter(c.upstreamEnabled)
 
+	if upstream.MaxConcurrentRequests > 0 {
+		timeout := time.Duration(upstream.ConcurrentRequestsTimeout) * time.Second
+		c.limiter = newLimiter(upstream.MaxConcurrentRequests, timeout)
+	}
+
+	if upstream.CircuitBreakerFailures > 0 {
+		cooldown := time.Duration(upstream.CircuitBreakerCooldown) * time.Second
+		c.breaker = newBreaker(upstream.CircuitBreakerFailures, cooldown, notifyUpstreamAvailability)
+	}
+
+	if upstream.CacheEnabled {
+		c.cache = newCache(cacheTTLFromConfig(upstream.CacheTTL), defaultCacheSize)
+	}
+	c.coalescer = newCoalescer(CoalescedMethods)
+
 	return &c, nil
 }
 
@@ -80,17 +157,50 @@ func (c *Client) Call(result interface{}, method string, args ...interface{}) er
 // The result must be a pointer so that package json can unmarshal into it. You
 // can also pass nil, in which case the result is ignored.
 //
-// It uses custom routing scheme for calls.
+// It uses custom routing scheme for calls. Every call passes through
+// registered middlewares first.
 func (c *Client) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return c.callHandler()(ctx, result, method, args...)
+}
+
+// callContext routes the call either to a locally registered handler,
+// to the upstream or to the local node.
+func (c *Client) callContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	// don't start a call if context is already canceled
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	// check locally registered handlers first
 	if handler, ok := c.handler(method); ok {
-		return c.callMethod(ctx, result, handler, args...)
+		return c.callMethod(ctx, result, method, handler, args...)
 	}
 
 	if c.router.routeRemote(method) {
-		return c.upstream.CallContext(ctx, result, method, args...)
+		return c.callUpstream(ctx, result, method, args...)
+	}
+	if c.isUnknownMethod(method) {
+		return newMethodNotFoundError(method)
+	}
+	return newError(c.local.CallContext(ctx, result, method, args...))
+}
+
+// callUpstream calls the upstream, respecting the limit of concurrent
+// calls and the circuit breaker.
+func (c *Client) callUpstream(ctx context.Context, result interface{}, method string, args ...interface{}) (err error) {
+	if c.breaker != nil {
+		if err := c.breaker.allow(); err != nil {
+			return err
+		}
+		defer func() { c.breaker.done(ctx, err) }()
 	}
-	return c.local.CallContext(ctx, result, method, args...)
+	if c.limiter != nil {
+		if err := c.limiter.acquire(ctx); err != nil {
+			return err
+		}
+		defer c.limiter.release()
+	}
+	return newError(c.upstreamClient().CallContext(ctx, result, method, args...))
 }
 
 // RegisterHandler registers local handler for specific RPC method.
@@ -104,12 +214,153 @@ func (c *Client) RegisterHandler(method string, handler Handler) {
 	c.handlers[method] = handler
 }
 
+// Use registers middleware that intercepts every JSON-RPC call made
+// through Call, CallContext and CallRaw.
+//
+// Middlewares are applied in order of registration, i.e. the first
+// registered middleware is the first to see a call. All of them see
+// every call, including ones served from the cache or shared with
+// an identical in-flight call.
+func (c *Client) Use(middleware Middleware) {
+	c.middlewaresMx.Lock()
+	defer c.middlewaresMx.Unlock()
+
+	c.middlewares = append(c.middlewares, middleware)
+}
+
+// SwitchUpstream connects to a new upstream server, using the configured
+// headers, and routes all upstream calls to it. Cached results are removed
+// as they may be no longer valid for the new upstream.
+func (c *Client) SwitchUpstream(url string) error {
+	upstream, err := c.connectUpstream(url)
+	if err != nil {
+		return err
+	}
+	c.setUpstream(upstream, url)
+	return nil
+}
+
+// SwitchUpstreamChain switches upstream like SwitchUpstream does, but only
+// if the new upstream serves the chain with the given id. Otherwise,
+// ErrUpstreamChainMismatch is returned and the current upstream is kept.
+func (c *Client) SwitchUpstreamChain(ctx context.Context, url string, chainID uint64) error {
+	upstream, err := c.connectUpstream(url)
+	if err != nil {
+		return err
+	}
+
+	var actual hexutil.Uint64
+	if err := upstream.CallContext(ctx, &actual, "eth_chainId"); err != nil {
+		upstream.Close()
+		return fmt.Errorf("get chain id of upstream: %s", err)
+	}
+	if uint64(actual) != chainID {
+		upstream.Close()
+		return ErrUpstreamChainMismatch
+	}
+
+	c.setUpstream(upstream, url)
+	return nil
+}
+
+// connectUpstream dials a new upstream server if it is allowed.
+func (c *Client) connectUpstream(url string) (*gethrpc.Client, error) {
+	if !c.upstreamEnabled {
+		return nil, ErrUpstreamDisabled
+	}
+	if !isUpstreamAllowed(url, c.allowedURLs) {
+		return nil, ErrUpstreamNotAllowed
+	}
+
+	upstream, err := c.dialUpstream(url)
+	if err != nil {
+		return nil, fmt.Errorf("dial upstream server: %s", err)
+	}
+	return upstream, nil
+}
+
+// setUpstream routes all upstream calls to the new upstream and forgets
+// results of the previous one.
+func (c *Client) setUpstream(upstream *gethrpc.Client, url string) {
+	c.upstreamMx.Lock()
+	previous := c.upstream
+	c.upstream = upstream
+	c.upstreamURL = url
+	c.upstreamMx.Unlock()
+
+	c.ClearCache()
+	c.head.reset()
+	previous.Close()
+}
+
+// upstreamClient is a concurrently safe method to get upstream client.
+func (c *Client) upstreamClient() *gethrpc.Client {
+	c.upstreamMx.RLock()
+	defer c.upstreamMx.RUnlock()
+	return c.upstream
+}
+
+// ClearCache removes all cached results of read-only calls.
+// It must be called whenever the upstream changes as cached
+// results may be no longer valid.
+func (c *Client) ClearCache() {
+	if c.cache == nil {
+		return
+	}
+	c.cache.clear()
+}
+
+// callHandlerContext calls local handler. If the context is canceled before
+// handler of a read-only method returns, it returns context's error immediately,
+// leaving handler to finish in background. Other handlers may have side effects,
+// e.g. send a transaction, so they are waited for and must honor the context.
+func (c *Client) callHandlerContext(ctx context.Context, method string, handler Handler, args ...interface{}) (interface{}, error) {
+	// context can't be canceled, no need to wait in a separate goroutine
+	if ctx.Done() == nil || !ReadOnlyMethods[method] {
+		return handler(ctx, args...)
+	}
+
+	type handlerResult struct {
+		response interface{}
+		err      error
+	}
+	done := make(chan handlerResult, 1)
+	go func() {
+		response, err := handler(ctx, args...)
+		done <- handlerResult{response, err}
+	}()
+
+	select {
+	case rst := <-done:
+		return rst.response, rst.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// callHandler is a concurrently safe method to build the middleware chain.
+func (c *Client) callHandler() CallHandler {
+	c.middlewaresMx.RLock()
+	defer c.middlewaresMx.RUnlock()
+
+	// cache and coalescer are the innermost, so they never hide a call
+	// from registered middlewares
+	handler := c.coalescer.middleware(c.callContext)
+	if c.cache != nil {
+		handler = c.cache.middleware(handler)
+	}
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		handler = c.middlewares[i](handler)
+	}
+	return handler
+}
+
 // callMethod calls registered RPC handler with given args and pointer to result.
 // It handles proper params and result converting
 //
 // TODO(divan): use cancellation via context here?
-func (c *Client) callMethod(ctx context.Context, result interface{}, handler Handler, args ...interface{}) error {
-	response, err := handler(ctx, args...)
+func (c *Client) callMethod(ctx context.Context, result interface{}, method string, handler Handler, args ...interface{}) error {
+	response, err := c.callHandlerContext(ctx, method, handler, args...)
 	if err != nil {
 		return err
 	}
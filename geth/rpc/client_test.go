@@ -0,0 +1,88 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddlewares(t *testing.T) {
+	c, err := NewClient(nil, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+	c.RegisterHandler("test_echo", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		return args[0], nil
+	})
+
+	var calls []string
+	c.Use(func(next CallHandler) CallHandler {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			calls = append(calls, "first:"+method)
+			return next(ctx, result, method, args...)
+		}
+	})
+	c.Use(func(next CallHandler) CallHandler {
+		return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+			calls = append(calls, "second:"+method)
+			if method == "test_blocked" {
+				return errors.New("blocked")
+			}
+			// rewrite arguments
+			return next(ctx, result, method, "rewritten")
+		}
+	})
+
+	var result string
+	require.NoError(t, c.Call(&result, "test_echo", "original"))
+	require.Equal(t, "rewritten", result)
+	require.EqualError(t, c.Call(&result, "test_blocked"), "blocked")
+	require.Equal(t, []string{"first:test_echo", "second:test_echo", "first:test_blocked", "second:test_blocked"}, calls)
+
+	raw := c.CallRaw(`{"jsonrpc": "2.0", "id": 1, "method": "test_echo", "params": ["original"]}`)
+	require.Equal(t, `{"jsonrpc":"2.0","id":1,"result":"rewritten"}`, raw)
+}
+
+func TestCallContextCancellation(t *testing.T) {
+	c, err := NewClient(nil, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	defer close(release)
+	var calls int32
+	c.RegisterHandler("eth_getBalance", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return nil, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	require.Equal(t, context.DeadlineExceeded, c.CallContext(ctx, nil, "eth_getBalance"))
+	require.True(t, time.Since(start) < time.Second, "call is not canceled in time")
+
+	// already canceled context doesn't start a call
+	require.Equal(t, context.DeadlineExceeded, c.CallContext(ctx, nil, "eth_getBalance"))
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestCallContextCancellationWaitsForSideEffects(t *testing.T) {
+	c, err := NewClient(nil, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+
+	sent := errors.New("sent regardless of cancellation")
+	c.RegisterHandler("eth_sendTransaction", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		return nil, sent
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	// caller learns the real outcome of the handler
+	require.Equal(t, sent, c.CallContext(ctx, nil, "eth_sendTransaction"))
+}
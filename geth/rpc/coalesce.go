@@ -0,0 +1,107 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// CoalescedMethods defines read-only methods which identical concurrent
+// calls are coalesced into a single call.
+var CoalescedMethods = map[string]bool{
+	"eth_call":       true,
+	"eth_getBalance": true,
+}
+
+// inflightCall is a call which result is shared by all callers.
+type inflightCall struct {
+	done   chan struct{} // closed when call is finished
+	result json.RawMessage
+	err    error
+}
+
+// coalescer makes sure that only one of identical calls is in flight.
+type coalescer struct {
+	mu      sync.Mutex // guards calls
+	calls   map[string]*inflightCall
+	methods map[string]bool
+}
+
+// newCoalescer creates coalescer for the given methods.
+func newCoalescer(methods map[string]bool) *coalescer {
+	return &coalescer{
+		calls:   make(map[string]*inflightCall),
+		methods: methods,
+	}
+}
+
+// middleware returns Middleware which coalesces identical in-flight calls.
+// Results, including errors, are shared only by callers which arrived while
+// a call was in flight, so an error is never kept for later calls. A call
+// is made with the context of the caller which started it, so if it's
+// canceled, waiting callers retry the call with their own contexts.
+func (c *coalescer) middleware(next CallHandler) CallHandler {
+	return func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+		if !c.methods[method] {
+			return next(ctx, result, method, args...)
+		}
+
+		key, err := cacheKey(method, args)
+		if err != nil {
+			return next(ctx, result, method, args...)
+		}
+
+		call, err := c.call(ctx, key, func(call *inflightCall) error {
+			return next(ctx, &call.result, method, args...)
+		})
+		if err != nil {
+			return err
+		}
+		if call.err != nil {
+			return call.err
+		}
+		if result == nil {
+			return nil
+		}
+		return json.Unmarshal(call.result, result)
+	}
+}
+
+// call returns in-flight call with the key, or starts it with do if there is
+// none. Returned error is ctx's error if ctx is done while waiting for the call.
+func (c *coalescer) call(ctx context.Context, key string, do func(*inflightCall) error) (*inflightCall, error) {
+	for {
+		c.mu.Lock()
+		call, ok := c.calls[key]
+		if !ok {
+			call = &inflightCall{done: make(chan struct{})}
+			c.calls[key] = call
+		}
+		c.mu.Unlock()
+
+		if !ok {
+			call.err = do(call)
+
+			c.mu.Lock()
+			delete(c.calls, key)
+			c.mu.Unlock()
+			close(call.done)
+			return call, nil
+		}
+
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if !isContextError(call.err) || ctx.Err() != nil {
+			return call, nil
+		}
+		// the call was canceled by its caller, not because of the result
+	}
+}
+
+// isContextError returns true if err is caused by a canceled context.
+func isContextError(err error) bool {
+	return err == context.Canceled || err == context.DeadlineExceeded
+}
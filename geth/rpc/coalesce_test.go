@@ -0,0 +1,102 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoalescedCalls(t *testing.T) {
+	c, err := NewClient(nil, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+
+	var calls int32
+	release := make(chan struct{})
+	failure := errors.New("upstream failure")
+	fail := true
+	c.RegisterHandler("eth_getBalance", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		if fail {
+			return nil, failure
+		}
+		return "0x10", nil
+	})
+
+	const callers = 5
+	callAll := func() []error {
+		var wg sync.WaitGroup
+		errs := make([]error, callers)
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				var balance string
+				errs[i] = c.Call(&balance, "eth_getBalance", "0xb60e8dd61c5d32be8058bb8eb970870f07233155", "latest")
+				if errs[i] == nil && balance != "0x10" {
+					errs[i] = errors.New("unexpected balance " + balance)
+				}
+			}(i)
+		}
+		// give all callers time to join the in-flight call
+		time.Sleep(100 * time.Millisecond)
+		release <- struct{}{}
+		wg.Wait()
+		return errs
+	}
+
+	// error is propagated to every caller
+	for _, err := range callAll() {
+		require.Equal(t, failure, err)
+	}
+	require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// error is not kept once the call is finished
+	fail = false
+	for _, err := range callAll() {
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestCoalescedCallCanceledByLeader(t *testing.T) {
+	var calls int32
+	next := func(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			// first call waits until its caller gives up
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		*result.(*json.RawMessage) = json.RawMessage(`"0x10"`)
+		return nil
+	}
+	handler := newCoalescer(CoalescedMethods).middleware(next)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leader := make(chan error, 1)
+	go func() {
+		leader <- handler(ctx, nil, "eth_getBalance", "0x01", "latest")
+	}()
+	// let the leader start the call
+	time.Sleep(50 * time.Millisecond)
+
+	follower := make(chan error, 1)
+	var balance string
+	go func() {
+		follower <- handler(context.Background(), &balance, "eth_getBalance", "0x01", "latest")
+	}()
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	require.Equal(t, context.Canceled, <-leader)
+	require.NoError(t, <-follower)
+	require.Equal(t, "0x10", balance)
+	require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,66 @@
+package rpc
+
+import (
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// Error is a JSON-RPC error returned by the upstream or the local node.
+// Unlike errors of go-ethereum's client, it exposes error data, which
+// contains e.g. revert data of eth_call and eth_estimateGas.
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ErrorCode returns JSON-RPC error code.
+func (e *Error) ErrorCode() int {
+	return e.Code
+}
+
+// RevertData returns data of a reverted call. Servers put it into
+// error data as a hex string.
+func (e *Error) RevertData() ([]byte, bool) {
+	str, ok := e.Data.(string)
+	if !ok {
+		return nil, false
+	}
+	data, err := hexutil.Decode(str)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// newError converts JSON-RPC error returned by go-ethereum's client
+// to Error. Other errors are returned as they are.
+func newError(err error) error {
+	rpcErr, ok := err.(gethrpc.Error)
+	if !ok {
+		return err
+	}
+	return &Error{
+		Code:    rpcErr.ErrorCode(),
+		Message: rpcErr.Error(),
+		Data:    errorData(rpcErr),
+	}
+}
+
+// dataError is a JSON-RPC error with data, see 0021-rpc-error-data.patch.
+type dataError interface {
+	ErrorData() interface{}
+}
+
+// errorData returns data of go-ethereum's JSON-RPC error.
+func errorData(err gethrpc.Error) interface{} {
+	if e, ok := err.(dataError); ok {
+		return e.ErrorData()
+	}
+	return nil
+}
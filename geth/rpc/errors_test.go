@@ -0,0 +1,36 @@
+package rpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":3,"message":"execution reverted","data":"0x08c379a0"}}`)) //nolint: errcheck
+	}))
+	defer server.Close()
+
+	c, err := NewClient(nil, params.UpstreamRPCConfig{Enabled: true, URL: server.URL})
+	require.NoError(t, err)
+
+	var result string
+	err = c.Call(&result, "eth_estimateGas", map[string]interface{}{})
+	rpcErr, ok := err.(*Error)
+	require.True(t, ok, "unexpected error: %v", err)
+	require.Equal(t, 3, rpcErr.ErrorCode())
+	require.Equal(t, "execution reverted", rpcErr.Error())
+	data, ok := rpcErr.RevertData()
+	require.True(t, ok)
+	require.Equal(t, []byte{0x08, 0xc3, 0x79, 0xa0}, data)
+}
+
+func TestNewErrorKeepsOtherErrors(t *testing.T) {
+	require.Equal(t, ErrUpstreamBusy, newError(ErrUpstreamBusy))
+	require.Nil(t, newError(nil))
+}
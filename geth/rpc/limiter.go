@@ -0,0 +1,56 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrUpstreamBusy is returned when a call waits for the upstream
+// longer than allowed because of too many concurrent calls.
+var ErrUpstreamBusy = errors.New("too many concurrent upstream requests")
+
+// limiter bounds the number of simultaneous upstream calls.
+type limiter struct {
+	slots   chan struct{}
+	timeout time.Duration // zero value means waiting until context is done
+}
+
+// newLimiter creates limiter which allows max simultaneous calls.
+func newLimiter(max int, timeout time.Duration) *limiter {
+	return &limiter{
+		slots:   make(chan struct{}, max),
+		timeout: timeout,
+	}
+}
+
+// acquire waits until there is a free slot for a call. It returns
+// ErrUpstreamBusy if timeout expires or context error if it is done.
+func (l *limiter) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	var expired <-chan time.Time
+	if l.timeout > 0 {
+		timer := time.NewTimer(l.timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-expired:
+		return ErrUpstreamBusy
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot acquired by acquire.
+func (l *limiter) release() {
+	<-l.slots
+}
@@ -0,0 +1,37 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter(t *testing.T) {
+	l := newLimiter(2, 10*time.Millisecond)
+	require.NoError(t, l.acquire(context.Background()))
+	require.NoError(t, l.acquire(context.Background()))
+
+	// no free slots, waits for timeout
+	require.Equal(t, ErrUpstreamBusy, l.acquire(context.Background()))
+
+	// context is done before timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l.timeout = time.Minute
+	require.Equal(t, context.Canceled, l.acquire(ctx))
+
+	// queued call proceeds once a slot is released
+	done := make(chan error)
+	go func() {
+		done <- l.acquire(context.Background())
+	}()
+	l.release()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("queued call is not unblocked")
+	}
+}
@@ -0,0 +1,149 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// LogsBlockRange is the largest range of blocks queried by a single
+// eth_getLogs call. Larger ranges are split, as providers limit them.
+const LogsBlockRange = 5000
+
+// MaxLogsBlockRange is the largest range of blocks queried by GetLogs.
+const MaxLogsBlockRange = 100 * LogsBlockRange
+
+var (
+	// ErrMissingFromBlock is returned when a logs query has no from block.
+	ErrMissingFromBlock = errors.New("from block of logs query is missing")
+	// ErrLogsRangeTooLarge is returned when a logs query spans more than MaxLogsBlockRange blocks.
+	ErrLogsRangeTooLarge = fmt.Errorf("logs query spans more than %d blocks", MaxLogsBlockRange)
+)
+
+// TransferEventTopic is the topic of ERC20 Transfer(address,address,uint256) event.
+var TransferEventTopic = crypto.Keccak256Hash([]byte("Transfer(address,address,uint256)"))
+
+// NewFilterQuery returns a query of logs of the given contract. Each of topics,
+// if not nil, must match the topic in the same position.
+func NewFilterQuery(contract common.Address, topics ...*common.Hash) ethereum.FilterQuery {
+	query := ethereum.FilterQuery{Addresses: []common.Address{contract}}
+	for _, topic := range topics {
+		if topic == nil {
+			query.Topics = append(query.Topics, nil)
+			continue
+		}
+		query.Topics = append(query.Topics, []common.Hash{*topic})
+	}
+	return query
+}
+
+// AddressTopic returns topic of an indexed address argument of an event.
+func AddressTopic(address common.Address) *common.Hash {
+	topic := common.BytesToHash(address.Bytes())
+	return &topic
+}
+
+// GetLogs returns logs matching the given query. Ranges larger than
+// LogsBlockRange are split into several eth_getLogs calls. FromBlock is
+// required, nil ToBlock means the latest block, and ranges larger than
+// MaxLogsBlockRange are refused.
+func (c *Client) GetLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	if query.FromBlock == nil {
+		return nil, ErrMissingFromBlock
+	}
+	from := query.FromBlock.Uint64()
+	var to uint64
+	if query.ToBlock != nil {
+		to = query.ToBlock.Uint64()
+	} else {
+		latest, err := c.BlockNumber(ctx)
+		if err != nil {
+			return nil, err
+		}
+		to = latest
+	}
+	if to >= from && to-from >= MaxLogsBlockRange {
+		return nil, ErrLogsRangeTooLarge
+	}
+
+	var logs []types.Log
+	for start := from; start <= to; start += LogsBlockRange {
+		end := start + LogsBlockRange - 1
+		if end > to {
+			end = to
+		}
+		chunk := query
+		chunk.FromBlock = new(big.Int).SetUint64(start)
+		chunk.ToBlock = new(big.Int).SetUint64(end)
+
+		var result []types.Log
+		if err := c.CallContext(ctx, &result, "eth_getLogs", toFilterArg(chunk)); err != nil {
+			return nil, err
+		}
+		logs = append(logs, result...)
+	}
+	return logs, nil
+}
+
+// GetTokenTransfers returns Transfer events of the given token sent from or to the account,
+// ordered by their position in the blockchain. The range of blocks is limited as in GetLogs.
+func (c *Client) GetTokenTransfers(ctx context.Context, token, account common.Address, fromBlock, toBlock *big.Int) ([]types.Log, error) {
+	outbound := NewFilterQuery(token, &TransferEventTopic, AddressTopic(account))
+	inbound := NewFilterQuery(token, &TransferEventTopic, nil, AddressTopic(account))
+
+	var logs []types.Log
+	for _, query := range []ethereum.FilterQuery{outbound, inbound} {
+		query.FromBlock, query.ToBlock = fromBlock, toBlock
+		result, err := c.GetLogs(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, result...)
+	}
+	return mergeLogs(logs), nil
+}
+
+// mergeLogs orders logs by their position in the blockchain
+// and removes duplicates, e.g. transfers to self.
+func mergeLogs(logs []types.Log) []types.Log {
+	sort.Slice(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+	merged := logs[:0]
+	for _, log := range logs {
+		if n := len(merged); n > 0 && log.BlockHash == merged[n-1].BlockHash && log.Index == merged[n-1].Index {
+			continue
+		}
+		merged = append(merged, log)
+	}
+	return merged
+}
+
+func toFilterArg(q ethereum.FilterQuery) interface{} {
+	arg := map[string]interface{}{
+		"address": q.Addresses,
+		"topics":  q.Topics,
+	}
+	if q.FromBlock == nil {
+		arg["fromBlock"] = "0x0"
+	} else {
+		arg["fromBlock"] = hexutil.EncodeBig(q.FromBlock)
+	}
+	if q.ToBlock == nil {
+		arg["toBlock"] = "latest"
+	} else {
+		arg["toBlock"] = hexutil.EncodeBig(q.ToBlock)
+	}
+	return arg
+}
@@ -0,0 +1,90 @@
+package rpc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLogsChunks(t *testing.T) {
+	c, err := NewClient(nil, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+
+	var ranges [][2]string
+	c.RegisterHandler("eth_getLogs", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		arg := args[0].(map[string]interface{})
+		ranges = append(ranges, [2]string{arg["fromBlock"].(string), arg["toBlock"].(string)})
+		return []types.Log{{BlockNumber: uint64(len(ranges))}}, nil
+	})
+
+	query := NewFilterQuery(common.HexToAddress("0x01"))
+	query.FromBlock = big.NewInt(100)
+	query.ToBlock = big.NewInt(100 + LogsBlockRange + 1)
+	logs, err := c.GetLogs(context.Background(), query)
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	require.Equal(t, [][2]string{
+		{hexutil.EncodeUint64(100), hexutil.EncodeUint64(100 + LogsBlockRange - 1)},
+		{hexutil.EncodeUint64(100 + LogsBlockRange), hexutil.EncodeUint64(100 + LogsBlockRange + 1)},
+	}, ranges)
+}
+
+func TestGetLogsLimits(t *testing.T) {
+	c, err := NewClient(nil, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+
+	calls := 0
+	c.RegisterHandler("eth_getLogs", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		calls++
+		return []types.Log{}, nil
+	})
+
+	query := NewFilterQuery(common.HexToAddress("0x01"))
+	query.ToBlock = big.NewInt(100)
+	_, err = c.GetLogs(context.Background(), query)
+	require.Equal(t, ErrMissingFromBlock, err)
+
+	query.FromBlock = big.NewInt(100)
+	query.ToBlock = big.NewInt(100 + MaxLogsBlockRange)
+	_, err = c.GetLogs(context.Background(), query)
+	require.Equal(t, ErrLogsRangeTooLarge, err)
+	require.Equal(t, 0, calls)
+
+	query.ToBlock = big.NewInt(100 + MaxLogsBlockRange - 1)
+	_, err = c.GetLogs(context.Background(), query)
+	require.NoError(t, err)
+	require.Equal(t, MaxLogsBlockRange/LogsBlockRange, calls)
+}
+
+func TestNewFilterQuery(t *testing.T) {
+	contract := common.HexToAddress("0x01")
+	account := common.HexToAddress("0x02")
+	query := NewFilterQuery(contract, &TransferEventTopic, nil, AddressTopic(account))
+	require.Equal(t, []common.Address{contract}, query.Addresses)
+	require.Equal(t, [][]common.Hash{
+		{TransferEventTopic},
+		nil,
+		{common.HexToHash("0x02")},
+	}, query.Topics)
+}
+
+func TestMergeLogs(t *testing.T) {
+	self := types.Log{BlockNumber: 1, Index: 1}
+	logs := mergeLogs([]types.Log{
+		{BlockNumber: 2, Index: 0},
+		self,
+		{BlockNumber: 1, Index: 0},
+		self,
+	})
+	require.Equal(t, []types.Log{
+		{BlockNumber: 1, Index: 0},
+		self,
+		{BlockNumber: 2, Index: 0},
+	}, logs)
+}
@@ -0,0 +1,84 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// methodNotFoundCode is JSON-RPC error code returned for unsupported methods.
+const methodNotFoundCode = -32601
+
+// ErrPendingTransactionsUnsupported is returned when the node doesn't
+// expose its pool of pending transactions.
+var ErrPendingTransactionsUnsupported = errors.New("pending transactions are not available")
+
+// PendingTx is a transaction which is not mined yet.
+type PendingTx struct {
+	Hash     common.Hash     `json:"hash"`
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Nonce    hexutil.Uint64  `json:"nonce"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Value    *hexutil.Big    `json:"value"`
+	Input    hexutil.Bytes   `json:"input"`
+}
+
+// txPoolContent is a result of txpool_content, transactions are
+// grouped by sender and nonce.
+type txPoolContent struct {
+	Pending map[common.Address]map[string]PendingTx `json:"pending"`
+	Queued  map[common.Address]map[string]PendingTx `json:"queued"`
+}
+
+// GetPendingTransactions returns transactions of the account which are not mined yet,
+// ordered by nonce. It uses txpool_content, or parity_pendingTransactions if the former
+// is not supported. ErrPendingTransactionsUnsupported is returned if neither is supported.
+func (c *Client) GetPendingTransactions(ctx context.Context, address common.Address) ([]PendingTx, error) {
+	var txs []PendingTx
+
+	var content txPoolContent
+	err := c.CallContext(ctx, &content, "txpool_content")
+	if err == nil {
+		for _, pool := range []map[common.Address]map[string]PendingTx{content.Pending, content.Queued} {
+			for _, tx := range pool[address] {
+				txs = append(txs, tx)
+			}
+		}
+		return sortByNonce(txs), nil
+	}
+	if !isMethodNotFound(err) {
+		return nil, err
+	}
+
+	var all []PendingTx
+	err = c.CallContext(ctx, &all, "parity_pendingTransactions")
+	if isMethodNotFound(err) {
+		return nil, ErrPendingTransactionsUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, tx := range all {
+		if tx.From == address {
+			txs = append(txs, tx)
+		}
+	}
+	return sortByNonce(txs), nil
+}
+
+func isMethodNotFound(err error) bool {
+	rpcErr, ok := err.(*Error)
+	return ok && rpcErr.Code == methodNotFoundCode
+}
+
+func sortByNonce(txs []PendingTx) []PendingTx {
+	sort.Slice(txs, func(i, j int) bool {
+		return txs[i].Nonce < txs[j].Nonce
+	})
+	return txs
+}
@@ -0,0 +1,57 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+var errMethodNotFound = &Error{Code: methodNotFoundCode, Message: "method not found"}
+
+func TestGetPendingTransactionsTxPool(t *testing.T) {
+	c, err := NewClient(nil, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+	address := common.HexToAddress("0x01")
+
+	other := common.HexToAddress("0x02")
+
+	c.RegisterHandler("txpool_content", func(context.Context, ...interface{}) (interface{}, error) {
+		return txPoolContent{
+			Pending: map[common.Address]map[string]PendingTx{
+				address: {"2": {From: address, Nonce: 2}},
+				other:   {"1": {From: other, Nonce: 1}},
+			},
+			Queued: map[common.Address]map[string]PendingTx{
+				address: {"5": {From: address, Nonce: 5}, "4": {From: address, Nonce: 4}},
+			},
+		}, nil
+	})
+	txs, err := c.GetPendingTransactions(context.Background(), address)
+	require.NoError(t, err)
+	require.Equal(t, []PendingTx{{From: address, Nonce: 2}, {From: address, Nonce: 4}, {From: address, Nonce: 5}}, txs)
+}
+
+func TestGetPendingTransactionsFallback(t *testing.T) {
+	c, err := NewClient(nil, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+	address := common.HexToAddress("0x01")
+
+	c.RegisterHandler("txpool_content", func(context.Context, ...interface{}) (interface{}, error) {
+		return nil, errMethodNotFound
+	})
+	c.RegisterHandler("parity_pendingTransactions", func(context.Context, ...interface{}) (interface{}, error) {
+		return []PendingTx{{From: address, Nonce: 3}, {From: common.HexToAddress("0x02")}, {From: address, Nonce: 1}}, nil
+	})
+	txs, err := c.GetPendingTransactions(context.Background(), address)
+	require.NoError(t, err)
+	require.Equal(t, []PendingTx{{From: address, Nonce: 1}, {From: address, Nonce: 3}}, txs)
+
+	c.RegisterHandler("parity_pendingTransactions", func(context.Context, ...interface{}) (interface{}, error) {
+		return nil, errMethodNotFound
+	})
+	_, err = c.GetPendingTransactions(context.Background(), address)
+	require.Equal(t, ErrPendingTransactionsUnsupported, err)
+}
@@ -0,0 +1,36 @@
+package rpc
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// PingSamples is a number of calls made to measure upstream latency.
+const PingSamples = 3
+
+// PingUpstream measures latency of the upstream with a lightweight call.
+// It makes PingSamples calls and returns the median duration to smooth jitter.
+// Calls are made directly, bypassing the cache, the limiter and the circuit breaker.
+func (c *Client) PingUpstream(ctx context.Context) (time.Duration, error) {
+	if !c.upstreamEnabled {
+		return 0, ErrUpstreamDisabled
+	}
+
+	samples := make([]time.Duration, 0, PingSamples)
+	for i := 0; i < PingSamples; i++ {
+		var chainID hexutil.Big
+		start := time.Now()
+		if err := c.upstreamClient().CallContext(ctx, &chainID, "eth_chainId"); err != nil {
+			return 0, newError(err)
+		}
+		samples = append(samples, time.Since(start))
+	}
+
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i] < samples[j]
+	})
+	return samples[len(samples)/2], nil
+}
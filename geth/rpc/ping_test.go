@@ -0,0 +1,37 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPingUpstream(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x3"}`)) //nolint: errcheck
+	}))
+	defer server.Close()
+
+	c, err := NewClient(nil, params.UpstreamRPCConfig{Enabled: true, URL: server.URL})
+	require.NoError(t, err)
+
+	latency, err := c.PingUpstream(context.Background())
+	require.NoError(t, err)
+	require.True(t, latency > 0)
+	require.Equal(t, PingSamples, calls)
+}
+
+func TestPingUpstreamDisabled(t *testing.T) {
+	c, err := NewClient(nil, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+
+	_, err = c.PingUpstream(context.Background())
+	require.Equal(t, ErrUpstreamDisabled, err)
+}
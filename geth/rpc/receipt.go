@@ -0,0 +1,90 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	// ErrTransactionNotMined is returned when a receipt of the transaction is not available yet.
+	ErrTransactionNotMined = errors.New("transaction is not mined yet")
+
+	// ErrNotContractCreation is returned when the transaction didn't create a contract.
+	ErrNotContractCreation = errors.New("transaction is not a contract creation")
+)
+
+// contractReceipt is a part of transaction receipt needed to get a deployed contract.
+type contractReceipt struct {
+	ContractAddress *common.Address `json:"contractAddress"`
+}
+
+// GetContractAddress returns an address of the contract deployed by the transaction.
+func (c *Client) GetContractAddress(ctx context.Context, hash common.Hash) (common.Address, error) {
+	var receipt *contractReceipt
+	if err := c.CallContext(ctx, &receipt, "eth_getTransactionReceipt", hash); err != nil {
+		return common.Address{}, err
+	}
+	if receipt == nil {
+		return common.Address{}, ErrTransactionNotMined
+	}
+	if receipt.ContractAddress == nil {
+		return common.Address{}, ErrNotContractCreation
+	}
+	return *receipt.ContractAddress, nil
+}
+
+// TransactionFee is a fee actually paid by a mined transaction.
+type TransactionFee struct {
+	GasUsed uint64
+	// EffectiveGasPrice is a price paid per gas. It may be lower than the maximum
+	// fee of EIP-1559 transaction and is equal to gas price of a legacy one.
+	EffectiveGasPrice *big.Int
+	// Fee is GasUsed multiplied by EffectiveGasPrice.
+	Fee *big.Int
+}
+
+// feeReceipt is a part of transaction receipt needed to get a paid fee.
+type feeReceipt struct {
+	GasUsed           hexutil.Uint64 `json:"gasUsed"`
+	EffectiveGasPrice *hexutil.Big   `json:"effectiveGasPrice"`
+}
+
+// gasPriceTransaction is a part of transaction needed to get its gas price.
+type gasPriceTransaction struct {
+	GasPrice *hexutil.Big `json:"gasPrice"`
+}
+
+// GetTransactionFee returns a fee paid by the mined transaction. Receipts returned by nodes
+// without EIP-1559 support don't contain effective gas price, so gas price of the transaction is used.
+func (c *Client) GetTransactionFee(ctx context.Context, hash common.Hash) (TransactionFee, error) {
+	var receipt *feeReceipt
+	if err := c.CallContext(ctx, &receipt, "eth_getTransactionReceipt", hash); err != nil {
+		return TransactionFee{}, err
+	}
+	if receipt == nil {
+		return TransactionFee{}, ErrTransactionNotMined
+	}
+
+	price := receipt.EffectiveGasPrice
+	if price == nil {
+		var tx *gasPriceTransaction
+		if err := c.CallContext(ctx, &tx, "eth_getTransactionByHash", hash); err != nil {
+			return TransactionFee{}, err
+		}
+		if tx == nil || tx.GasPrice == nil {
+			return TransactionFee{}, ErrTransactionNotMined
+		}
+		price = tx.GasPrice
+	}
+
+	gasUsed := uint64(receipt.GasUsed)
+	return TransactionFee{
+		GasUsed:           gasUsed,
+		EffectiveGasPrice: price.ToInt(),
+		Fee:               new(big.Int).Mul(price.ToInt(), new(big.Int).SetUint64(gasUsed)),
+	}, nil
+}
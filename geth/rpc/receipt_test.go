@@ -0,0 +1,68 @@
+package rpc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetContractAddress(t *testing.T) {
+	c, err := NewClient(nil, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+
+	contract := common.HexToAddress("0x0a")
+	receipts := map[common.Hash]*contractReceipt{
+		common.HexToHash("0x01"): {ContractAddress: &contract},
+		common.HexToHash("0x02"): {},
+	}
+	c.RegisterHandler("eth_getTransactionReceipt", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		return receipts[args[0].(common.Hash)], nil
+	})
+
+	address, err := c.GetContractAddress(context.Background(), common.HexToHash("0x01"))
+	require.NoError(t, err)
+	require.Equal(t, contract, address)
+
+	_, err = c.GetContractAddress(context.Background(), common.HexToHash("0x02"))
+	require.Equal(t, ErrNotContractCreation, err)
+
+	_, err = c.GetContractAddress(context.Background(), common.HexToHash("0x03"))
+	require.Equal(t, ErrTransactionNotMined, err)
+}
+
+func TestGetTransactionFee(t *testing.T) {
+	c, err := NewClient(nil, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+
+	receipts := map[common.Hash]*feeReceipt{
+		common.HexToHash("0x01"): {GasUsed: 21000, EffectiveGasPrice: (*hexutil.Big)(big.NewInt(7))},
+		common.HexToHash("0x02"): {GasUsed: 21000},
+	}
+	c.RegisterHandler("eth_getTransactionReceipt", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		return receipts[args[0].(common.Hash)], nil
+	})
+	c.RegisterHandler("eth_getTransactionByHash", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		return &gasPriceTransaction{GasPrice: (*hexutil.Big)(big.NewInt(10))}, nil
+	})
+
+	// EIP-1559 receipt
+	fee, err := c.GetTransactionFee(context.Background(), common.HexToHash("0x01"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(21000), fee.GasUsed)
+	require.Equal(t, big.NewInt(7), fee.EffectiveGasPrice)
+	require.Equal(t, big.NewInt(147000), fee.Fee)
+
+	// legacy receipt
+	fee, err = c.GetTransactionFee(context.Background(), common.HexToHash("0x02"))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(10), fee.EffectiveGasPrice)
+	require.Equal(t, big.NewInt(210000), fee.Fee)
+
+	_, err = c.GetTransactionFee(context.Background(), common.HexToHash("0x03"))
+	require.Equal(t, ErrTransactionNotMined, err)
+}
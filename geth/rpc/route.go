@@ -89,4 +89,6 @@ var remoteMethods = [...]string{
 	"net_version",
 	"net_peerCount",
 	"net_listening",
+	"txpool_content",
+	"parity_pendingTransactions",
 }
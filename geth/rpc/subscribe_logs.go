@@ -0,0 +1,133 @@
+package rpc
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// LogsPollInterval is how often new logs are polled when calls are
+// served by the upstream, which doesn't support subscriptions.
+var LogsPollInterval = 5 * time.Second
+
+// SubscribeLogs streams new logs matching the query. Logs are received by
+// subscription when calls are served by the local node, otherwise they are
+// polled over a moving range of blocks, starting from query's FromBlock or
+// the latest block if it is nil. Query's ToBlock is ignored.
+//
+// The returned channel is closed when the stream ends, i.e. when the returned
+// function is called, the context is done or the subscription fails.
+func (c *Client) SubscribeLogs(ctx context.Context, query ethereum.FilterQuery) (<-chan types.Log, func(), error) {
+	out := make(chan types.Log)
+	quit := make(chan struct{})
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() { close(quit) })
+	}
+
+	if c.local != nil && !c.router.routeRemote("eth_getLogs") {
+		logs := make(chan types.Log)
+		sub, err := c.local.EthSubscribe(ctx, logs, "logs", toSubscriptionFilterArg(query))
+		if err == nil {
+			go c.forwardLogs(ctx, sub, logs, out, quit)
+			return out, unsubscribe, nil
+		}
+		c.log.Warn("failed to subscribe to logs, logs are polled", "err", err)
+	}
+
+	next := uint64(0)
+	if query.FromBlock != nil {
+		next = query.FromBlock.Uint64()
+	} else {
+		latest, err := c.BlockNumber(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		next = latest + 1
+	}
+	go c.pollLogs(ctx, query, next, out, quit)
+	return out, unsubscribe, nil
+}
+
+// forwardLogs sends logs received by subscription until the stream ends.
+func (c *Client) forwardLogs(ctx context.Context, sub ethereum.Subscription, logs <-chan types.Log, out chan<- types.Log, quit <-chan struct{}) {
+	defer close(out)
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case log := <-logs:
+			select {
+			case out <- log:
+			case <-quit:
+				return
+			case <-ctx.Done():
+				return
+			}
+		case err := <-sub.Err():
+			c.log.Warn("logs subscription is closed", "err", err)
+			return
+		case <-quit:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// pollLogs polls logs of blocks starting from next until the stream ends.
+func (c *Client) pollLogs(ctx context.Context, query ethereum.FilterQuery, next uint64, out chan<- types.Log, quit <-chan struct{}) {
+	defer close(out)
+	ticker := time.NewTicker(LogsPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-quit:
+			return
+		case <-ctx.Done():
+			return
+		}
+
+		latest, err := c.BlockNumber(ctx)
+		if err != nil {
+			c.log.Warn("failed to get the latest block to poll logs", "err", err)
+			continue
+		}
+		if latest < next {
+			continue
+		}
+		if latest-next >= MaxLogsBlockRange {
+			latest = next + MaxLogsBlockRange - 1
+		}
+		query.FromBlock = new(big.Int).SetUint64(next)
+		query.ToBlock = new(big.Int).SetUint64(latest)
+		logs, err := c.GetLogs(ctx, query)
+		if err != nil {
+			c.log.Warn("failed to poll logs", "err", err)
+			continue
+		}
+		for _, log := range logs {
+			select {
+			case out <- log:
+			case <-quit:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+		next = latest + 1
+	}
+}
+
+// toSubscriptionFilterArg returns logs subscription criteria, which
+// match logs of new blocks only.
+func toSubscriptionFilterArg(q ethereum.FilterQuery) interface{} {
+	return map[string]interface{}{
+		"address": q.Addresses,
+		"topics":  q.Topics,
+	}
+}
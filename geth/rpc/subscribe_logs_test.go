@@ -0,0 +1,55 @@
+package rpc
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeLogsPolling(t *testing.T) {
+	defer func(interval time.Duration) { LogsPollInterval = interval }(LogsPollInterval)
+	LogsPollInterval = 10 * time.Millisecond
+
+	c, err := NewClient(nil, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+	c.RegisterHandler("eth_blockNumber", func(context.Context, ...interface{}) (interface{}, error) {
+		return hexutil.Uint64(10), nil
+	})
+	ranges := make(chan [2]string, 10)
+	c.RegisterHandler("eth_getLogs", func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		arg := args[0].(map[string]interface{})
+		ranges <- [2]string{arg["fromBlock"].(string), arg["toBlock"].(string)}
+		return []types.Log{{BlockNumber: 7}}, nil
+	})
+
+	query := NewFilterQuery(common.HexToAddress("0x01"))
+	query.FromBlock = big.NewInt(5)
+	logs, unsubscribe, err := c.SubscribeLogs(context.Background(), query)
+	require.NoError(t, err)
+
+	select {
+	case log := <-logs:
+		require.Equal(t, uint64(7), log.BlockNumber)
+	case <-time.After(time.Second):
+		require.FailNow(t, "timed out waiting for logs")
+	}
+	require.Equal(t, [2]string{"0x5", "0xa"}, <-ranges)
+
+	unsubscribe()
+	unsubscribe() // can be called more than once
+	for range logs {
+	}
+	// blocks which are already queried are not polled again
+	select {
+	case r := <-ranges:
+		require.FailNow(t, "unexpected poll", "range: %v", r)
+	default:
+	}
+}
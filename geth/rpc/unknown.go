@@ -0,0 +1,57 @@
+package rpc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RejectUnknownMethods makes the client fail calls of methods, which aren't
+// served by a registered handler, the upstream or any API module of the local
+// node, with "method not found" error instead of forwarding them to the local node.
+func (c *Client) RejectUnknownMethods(reject bool) {
+	c.unknownMx.Lock()
+	defer c.unknownMx.Unlock()
+
+	c.rejectUnknown = reject
+}
+
+// isUnknownMethod returns true if unknown methods are rejected and the method
+// belongs to none of API modules of the local node. If modules can't be queried,
+// no method is considered unknown.
+func (c *Client) isUnknownMethod(method string) bool {
+	c.unknownMx.Lock()
+	defer c.unknownMx.Unlock()
+
+	if !c.rejectUnknown {
+		return false
+	}
+	if c.modules == nil {
+		if c.local == nil {
+			return true
+		}
+		supported, err := c.local.SupportedModules()
+		if err != nil {
+			c.log.Warn("failed to get API modules of the local node", "err", err)
+			return false
+		}
+		c.modules = make(map[string]bool, len(supported))
+		for module := range supported {
+			c.modules[module] = true
+		}
+	}
+	return !c.modules[methodModule(method)]
+}
+
+// methodModule returns API module of the method, e.g. "eth" for "eth_call".
+func methodModule(method string) string {
+	return strings.SplitN(method, "_", 2)[0]
+}
+
+// newMethodNotFoundError returns the same error as the node returns
+// for a method it doesn't serve.
+func newMethodNotFoundError(method string) error {
+	return &Error{
+		Code:    methodNotFoundCode,
+		Message: fmt.Sprintf("the method %s does not exist/is not available", method),
+	}
+}
@@ -0,0 +1,47 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+type TestService struct{}
+
+func (TestService) Echo(s string) string {
+	return s
+}
+
+func TestRejectUnknownMethods(t *testing.T) {
+	server := gethrpc.NewServer()
+	require.NoError(t, server.RegisterName("test", TestService{}))
+	c, err := NewClient(gethrpc.DialInProc(server), params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+
+	var result string
+	require.NoError(t, c.Call(&result, "test_echo", "hello"))
+	require.Equal(t, "hello", result)
+
+	// unknown methods are passed through by default
+	err = c.Call(&result, "foo_bar")
+	require.IsType(t, &Error{}, err)
+	require.Equal(t, methodNotFoundCode, err.(*Error).Code)
+
+	c.RejectUnknownMethods(true)
+	require.NoError(t, c.Call(&result, "test_echo", "world"))
+	require.Equal(t, "world", result)
+	require.Equal(t, newMethodNotFoundError("foo_bar"), c.Call(&result, "foo_bar"))
+
+	// registered handlers are never rejected
+	c.RegisterHandler("status_ping", func(context.Context, ...interface{}) (interface{}, error) {
+		return "pong", nil
+	})
+	require.NoError(t, c.Call(&result, "status_ping"))
+	require.Equal(t, "pong", result)
+
+	raw := c.CallRaw(`{"jsonrpc": "2.0", "id": 1, "method": "foo_bar", "params": []}`)
+	require.Equal(t, `{"jsonrpc":"2.0","id":1,"error":{"code":-32601,"message":"the method foo_bar does not exist/is not available"}}`, raw)
+}
@@ -0,0 +1,81 @@
+package rpc
+
+import (
+	"net/http"
+	neturl "net/url"
+	"strings"
+
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// redactedHeaderValue replaces values of custom upstream headers in logs.
+const redactedHeaderValue = "<redacted>"
+
+// headerTransport attaches custom headers to every upstream HTTP request.
+type headerTransport struct {
+	headers map[string]string
+	next    http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper interface.
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// a request must not be modified by RoundTripper, so headers are set on a copy
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header)+len(t.headers))
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	for k, v := range t.headers {
+		r.Header.Set(k, v)
+	}
+	return t.next.RoundTrip(r)
+}
+
+// dialUpstream connects to the upstream server. Custom headers are
+// supported only for HTTP endpoints.
+func (c *Client) dialUpstream(url string) (*gethrpc.Client, error) {
+	c.log.Debug("Dialing upstream", "url", url, "headers", redactHeaders(c.upstreamHeaders))
+
+	if len(c.upstreamHeaders) == 0 || !isHTTPURL(url) {
+		return gethrpc.Dial(url)
+	}
+
+	return gethrpc.DialHTTPWithClient(url, &http.Client{
+		Transport: &headerTransport{
+			headers: c.upstreamHeaders,
+			next:    http.DefaultTransport,
+		},
+	})
+}
+
+// redactHeaders returns a copy of headers which is safe to log.
+func redactHeaders(headers map[string]string) map[string]string {
+	redacted := make(map[string]string, len(headers))
+	for k := range headers {
+		redacted[k] = redactedHeaderValue
+	}
+	return redacted
+}
+
+// isUpstreamAllowed checks if url matches any of allowed URLs or host names.
+func isUpstreamAllowed(url string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	var host string
+	if u, err := neturl.Parse(url); err == nil {
+		host = u.Hostname()
+	}
+	for _, a := range allowed {
+		if a == url || (host != "" && strings.EqualFold(a, host)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isHTTPURL(url string) bool {
+	return strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://")
+}
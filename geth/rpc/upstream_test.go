@@ -0,0 +1,100 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpstreamHeaders(t *testing.T) {
+	received := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get("X-Api-Key")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`)) //nolint: errcheck
+	}))
+	defer server.Close()
+
+	c, err := NewClient(nil, params.UpstreamRPCConfig{
+		Enabled: true,
+		URL:     server.URL,
+		Headers: map[string]string{"X-Api-Key": "secret"},
+	})
+	require.NoError(t, err)
+
+	var result string
+	require.NoError(t, c.Call(&result, "eth_gasPrice"))
+	require.Equal(t, "secret", <-received)
+
+	// headers are kept after upstream is switched
+	require.NoError(t, c.SwitchUpstream(server.URL))
+	require.NoError(t, c.Call(&result, "net_version"))
+	require.Equal(t, "secret", <-received)
+}
+
+func TestRedactHeaders(t *testing.T) {
+	redacted := redactHeaders(map[string]string{"X-Api-Key": "secret"})
+	require.Equal(t, map[string]string{"X-Api-Key": redactedHeaderValue}, redacted)
+}
+
+func TestUpstreamAllowedURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	_, err := NewClient(nil, params.UpstreamRPCConfig{
+		Enabled:     true,
+		URL:         server.URL,
+		AllowedURLs: []string{"mainnet.infura.io"},
+	})
+	require.Equal(t, ErrUpstreamNotAllowed, err)
+
+	c, err := NewClient(nil, params.UpstreamRPCConfig{
+		Enabled:     true,
+		URL:         server.URL,
+		AllowedURLs: []string{"127.0.0.1"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, ErrUpstreamNotAllowed, c.SwitchUpstream("https://mainnet.infura.io/v3"))
+	require.NoError(t, c.SwitchUpstream(server.URL))
+}
+
+func TestSwitchUpstreamChain(t *testing.T) {
+	newServer := func(chainID string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"` + chainID + `"}`)) //nolint: errcheck
+		}))
+	}
+	ropsten := newServer("0x3")
+	defer ropsten.Close()
+	rinkeby := newServer("0x4")
+	defer rinkeby.Close()
+
+	c, err := NewClient(nil, params.UpstreamRPCConfig{
+		Enabled: true,
+		URL:     ropsten.URL,
+	})
+	require.NoError(t, err)
+
+	// upstream of another chain is refused and the current one is kept
+	require.Equal(t, ErrUpstreamChainMismatch, c.SwitchUpstreamChain(context.Background(), rinkeby.URL, 3))
+	require.Equal(t, ropsten.URL, c.upstreamURL)
+
+	require.NoError(t, c.SwitchUpstreamChain(context.Background(), rinkeby.URL, 4))
+	require.Equal(t, rinkeby.URL, c.upstreamURL)
+}
+
+func TestIsUpstreamAllowed(t *testing.T) {
+	allowed := []string{"mainnet.infura.io", "https://ropsten.infura.io/v3/key"}
+
+	require.True(t, isUpstreamAllowed("https://ropsten.infura.io", nil))
+	require.True(t, isUpstreamAllowed("https://mainnet.infura.io/v3/key", allowed))
+	require.True(t, isUpstreamAllowed("wss://MAINNET.infura.io/ws", allowed))
+	require.True(t, isUpstreamAllowed("https://ropsten.infura.io/v3/key", allowed))
+	require.False(t, isUpstreamAllowed("https://ropsten.infura.io/v3/other", allowed))
+	require.False(t, isUpstreamAllowed("https://mainnet.infura.io.evil.com", allowed))
+}
@@ -0,0 +1,61 @@
+package signal
+
+import "sync"
+
+// Handler processes signals sent with Send.
+type Handler func(Envelope)
+
+// handlerEntry is a handler with an id used to remove it.
+type handlerEntry struct {
+	id      int
+	handler Handler
+}
+
+var (
+	handlersMutex sync.RWMutex // guards handlers and nextHandlerID
+	handlers      []handlerEntry
+	nextHandlerID int
+)
+
+// AddHandler adds a handler which is called for every signal, in addition to
+// the notification handler and other added handlers, e.g. to observe signals
+// for diagnostics without replacing the app's handler. Handlers are called
+// synchronously, in order they were added. Returned function removes the handler.
+func AddHandler(handler Handler) (remove func()) {
+	handlersMutex.Lock()
+	defer handlersMutex.Unlock()
+
+	id := nextHandlerID
+	nextHandlerID++
+	handlers = append(handlers, handlerEntry{id: id, handler: handler})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { removeHandler(id) })
+	}
+}
+
+// removeHandler removes the handler with the given id.
+func removeHandler(id int) {
+	handlersMutex.Lock()
+	defer handlersMutex.Unlock()
+
+	for i, entry := range handlers {
+		if entry.id == id {
+			handlers = append(handlers[:i:i], handlers[i+1:]...)
+			return
+		}
+	}
+}
+
+// dispatch calls all added handlers with the signal. Handlers are called
+// without holding the lock, so that they can add or remove handlers.
+func dispatch(signal Envelope) {
+	handlersMutex.RLock()
+	current := handlers
+	handlersMutex.RUnlock()
+
+	for _, entry := range current {
+		entry.handler(signal)
+	}
+}
@@ -30,6 +30,27 @@ const (
 
 	// EventChainDataRemoved is triggered when node's chain data is removed
 	EventChainDataRemoved = "chaindata.removed"
+
+	// EventChainSwitchRequested is triggered when wallet_switchEthereumChain
+	// waits for the app to confirm the switch
+	EventChainSwitchRequested = "chain.switch_requested"
+
+	// EventChainSwitched is triggered when upstream is switched to another chain
+	// by wallet_switchEthereumChain
+	EventChainSwitched = "chain.switched"
+
+	// EventUpstreamUnavailable is triggered when upstream failed too many times
+	// and calls to it fail fast
+	EventUpstreamUnavailable = "upstream.unavailable"
+
+	// EventUpstreamAvailable is triggered when upstream is reachable again
+	EventUpstreamAvailable = "upstream.available"
+
+	// EventSyncProgress is triggered periodically while node's blockchain is syncing
+	EventSyncProgress = "sync.progress"
+
+	// EventSyncCompleted is triggered when node's blockchain is synced
+	EventSyncCompleted = "sync.completed"
 )
 
 // Envelope is a general signal sent upward from node to RN app
@@ -43,6 +64,27 @@ type NodeCrashEvent struct {
 	Error error `json:"error"`
 }
 
+// ChainSwitchRequestedEvent is sent when a chain switch waits for confirmation
+type ChainSwitchRequestedEvent struct {
+	ID      string `json:"id"`
+	ChainID uint64 `json:"chainId"`
+	URL     string `json:"url"`
+}
+
+// ChainSwitchedEvent is sent when upstream is switched to another chain
+type ChainSwitchedEvent struct {
+	ChainID uint64 `json:"chainId"`
+	URL     string `json:"url"`
+}
+
+// SyncProgressEvent is sent while node's blockchain is syncing
+type SyncProgressEvent struct {
+	StartingBlock uint64  `json:"startingBlock"`
+	CurrentBlock  uint64  `json:"currentBlock"`
+	HighestBlock  uint64  `json:"highestBlock"`
+	Percentage    float64 `json:"percentage"`
+}
+
 // All general log messages in this package should be routed through this logger.
 var logger = log.New("package", "status-go/geth/signal")
 
@@ -59,23 +101,35 @@ func (e NodeCrashEvent) MarshalJSON() ([]byte, error) {
 // Events are encoded as JSON strings.
 type NodeNotificationHandler func(jsonEvent string)
 
-var notificationHandler NodeNotificationHandler = TriggerDefaultNodeNotificationHandler
+// removeNotificationHandler removes the notification handler added with AddHandler.
+var removeNotificationHandler func()
 
-// notificationHandlerMutex guards notificationHandler for concurrent calls
-var notificationHandlerMutex sync.RWMutex
+// notificationHandlerMutex guards removeNotificationHandler for concurrent calls
+var notificationHandlerMutex sync.Mutex
 
-// SetDefaultNodeNotificationHandler sets notification handler to invoke on Send
+func init() {
+	ResetDefaultNodeNotificationHandler()
+}
+
+// SetDefaultNodeNotificationHandler sets notification handler to invoke on Send.
+// It replaces the previous notification handler, handlers added with AddHandler
+// are kept.
 func SetDefaultNodeNotificationHandler(fn NodeNotificationHandler) {
 	notificationHandlerMutex.Lock()
-	notificationHandler = fn
-	notificationHandlerMutex.Unlock()
+	defer notificationHandlerMutex.Unlock()
+
+	if removeNotificationHandler != nil {
+		removeNotificationHandler()
+	}
+	removeNotificationHandler = AddHandler(func(signal Envelope) {
+		data, _ := json.Marshal(&signal)
+		fn(string(data))
+	})
 }
 
 // ResetDefaultNodeNotificationHandler sets notification handler to default one
 func ResetDefaultNodeNotificationHandler() {
-	notificationHandlerMutex.Lock()
-	notificationHandler = TriggerDefaultNodeNotificationHandler
-	notificationHandlerMutex.Unlock()
+	SetDefaultNodeNotificationHandler(TriggerDefaultNodeNotificationHandler)
 }
 
 // TriggerDefaultNodeNotificationHandler triggers default notification handler (helpful in tests)
@@ -83,18 +137,20 @@ func TriggerDefaultNodeNotificationHandler(jsonEvent string) {
 	logger.Info("Notification received", "event", jsonEvent)
 }
 
-// Send sends application signal (JSON, normally) upwards to application (via default notification handler)
+// Send sends application signal (JSON, normally) upwards to application and dispatches
+// it to the notification handler and handlers added with AddHandler.
 func Send(signal Envelope) {
 	data, _ := json.Marshal(&signal)
 	C.StatusServiceSignalEvent(C.CString(string(data)))
+	dispatch(signal)
 }
 
+// NotifyNode receives signals looped back by builds without an application,
+// which are already dispatched by Send.
 //export NotifyNode
 //nolint: golint
 func NotifyNode(jsonEvent *C.char) {
-	notificationHandlerMutex.RLock()
-	defer notificationHandlerMutex.RUnlock()
-	notificationHandler(C.GoString(jsonEvent))
+	logger.Debug("Signal looped back", "event", C.GoString(jsonEvent))
 }
 
 //export TriggerTestSignal
@@ -19,3 +19,42 @@ func TestNodeCrashEventJSONMarshalling(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expectedJSON, string(marshalled))
 }
+
+func TestAddHandler(t *testing.T) {
+	var legacy []string
+	SetDefaultNodeNotificationHandler(func(jsonEvent string) {
+		legacy = append(legacy, jsonEvent)
+	})
+	defer ResetDefaultNodeNotificationHandler()
+
+	var first, second []string
+	removeFirst := AddHandler(func(e Envelope) { first = append(first, e.Type) })
+	removeSecond := AddHandler(func(e Envelope) { second = append(second, e.Type) })
+	defer removeSecond()
+
+	Send(Envelope{Type: EventNodeStarted})
+	removeFirst()
+	removeFirst()
+	Send(Envelope{Type: EventNodeReady})
+
+	require.Equal(t, []string{EventNodeStarted}, first)
+	require.Equal(t, []string{EventNodeStarted, EventNodeReady}, second)
+	require.Equal(t, []string{`{"type":"node.started","event":null}`, `{"type":"node.ready","event":null}`}, legacy)
+}
+
+func TestSetNotificationHandlerReplacesPrevious(t *testing.T) {
+	defer ResetDefaultNodeNotificationHandler()
+
+	var first, second []string
+	SetDefaultNodeNotificationHandler(func(jsonEvent string) { first = append(first, jsonEvent) })
+	Send(Envelope{Type: EventNodeStarted})
+	SetDefaultNodeNotificationHandler(func(jsonEvent string) { second = append(second, jsonEvent) })
+	Send(Envelope{Type: EventNodeReady})
+
+	require.Equal(t, []string{`{"type":"node.started","event":null}`}, first)
+	require.Equal(t, []string{`{"type":"node.ready","event":null}`}, second)
+
+	handlersMutex.RLock()
+	defer handlersMutex.RUnlock()
+	require.Len(t, handlers, 1)
+}
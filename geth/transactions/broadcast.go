@@ -0,0 +1,180 @@
+package transactions
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// heldTx is a signed transaction waiting for broadcasting to be resumed.
+// Queued transaction stays in progress until it is sent or fails.
+type heldTx struct {
+	queuedTx    *QueuedTx
+	tx          *types.Transaction
+	skipIfMined bool // tx isn't sent if it is mined already, see SkipIfMinedKey
+}
+
+// heldBroadcast keeps transactions signed while broadcasting is paused.
+// Nonces of held transactions, including those being sent on resume, are
+// reserved, so that they aren't used by other transactions of the account.
+type heldBroadcast struct {
+	mu      sync.Mutex
+	paused  bool
+	txs     []heldTx // held until broadcasting is resumed
+	sending []heldTx // being sent by ResumeBroadcast
+}
+
+// PauseBroadcast makes completed transactions to be signed, but not sent
+// to the network until ResumeBroadcast is called. Held transactions stay in
+// the queue and are discarded with ErrShuttingDown if the manager is stopped.
+func (m *Manager) PauseBroadcast() {
+	m.broadcast.mu.Lock()
+	defer m.broadcast.mu.Unlock()
+
+	m.broadcast.paused = true
+}
+
+// ResumeBroadcast sends transactions held while broadcasting was paused,
+// in nonce order, and returns results per transaction hash. Held transactions
+// leave the queue with the result of sending. If a transaction fails, following
+// transactions of the same account aren't sent and fail with ErrPrecedingTxFailed.
+func (m *Manager) ResumeBroadcast() map[common.Hash]error {
+	m.broadcast.mu.Lock()
+	held := m.broadcast.txs
+	m.broadcast.txs = nil
+	m.broadcast.sending = append(m.broadcast.sending, held...)
+	m.broadcast.paused = false
+	m.broadcast.mu.Unlock()
+
+	sort.SliceStable(held, func(i, j int) bool {
+		return held[i].tx.Nonce() < held[j].tx.Nonce()
+	})
+	results := make(map[common.Hash]error, len(held))
+	failed := make(map[common.Address]bool)
+	for _, h := range held {
+		from := h.queuedTx.Args.From
+		var err error
+		if failed[from] {
+			err = ErrPrecedingTxFailed
+			m.finishHeld(h, err)
+		} else if err = m.sendHeld(h); err != nil {
+			failed[from] = true
+		}
+		results[h.tx.Hash()] = err
+	}
+	return results
+}
+
+// sendHeld sends the held transaction and finishes it with the result.
+func (m *Manager) sendHeld(h heldTx) error {
+	from := h.queuedTx.Args.From
+	m.addrLock.LockAddr(from)
+	defer m.addrLock.UnlockAddr(from)
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.rpcCallTimeout)
+	defer cancel()
+	err := m.sendSigned(context.WithValue(ctx, SkipIfMinedKey, h.skipIfMined), from, h.tx)
+	if isNonceTooLow(err) {
+		err = ErrNonceTooLow
+	}
+	if err != nil {
+		m.log.Warn("failed to broadcast held transaction", "id", h.queuedTx.ID, "hash", h.tx.Hash().Hex(), "err", err)
+		m.finishHeld(h, err)
+		return err
+	}
+	if m.config.DiscardSuperseded {
+		m.discardSuperseded(h.queuedTx, h.tx.Nonce())
+	}
+	m.txSent(h.queuedTx, h.tx.Hash())
+	m.finishHeld(h, nil)
+	return nil
+}
+
+// finishHeld releases the nonce of the held transaction and removes it from
+// the queue with the given result.
+func (m *Manager) finishHeld(h heldTx, err error) {
+	m.broadcast.mu.Lock()
+	m.broadcast.sending = removeHeld(m.broadcast.sending, h.queuedTx.ID)
+	m.broadcast.mu.Unlock()
+
+	var hash common.Hash
+	if err == nil {
+		hash = h.tx.Hash()
+	}
+	m.txDone(h.queuedTx, hash, err)
+}
+
+// discardHeld removes transactions held while broadcasting is paused from
+// the queue with the given error. Transactions being sent are not affected.
+func (m *Manager) discardHeld(err error) {
+	m.broadcast.mu.Lock()
+	held := m.broadcast.txs
+	m.broadcast.txs = nil
+	m.broadcast.mu.Unlock()
+
+	for _, h := range held {
+		m.log.Info("discard held transaction", "id", h.queuedTx.ID, "hash", h.tx.Hash().Hex(), "err", err)
+		m.txDone(h.queuedTx, common.Hash{}, err)
+	}
+}
+
+// holdBroadcast keeps signed transaction until broadcasting is resumed
+// and returns true if broadcasting is paused.
+func (m *Manager) holdBroadcast(h heldTx) bool {
+	m.broadcast.mu.Lock()
+	defer m.broadcast.mu.Unlock()
+
+	if !m.broadcast.paused {
+		return false
+	}
+	m.broadcast.txs = append(m.broadcast.txs, h)
+	m.log.Info("broadcasting is paused, transaction is held", "id", h.queuedTx.ID, "hash", h.tx.Hash().Hex())
+	if m.notify {
+		NotifyOnBroadcastHeld(h.queuedTx, h.tx.Hash(), h.tx.Nonce())
+	}
+	return true
+}
+
+// isHeld returns true if the transaction is held or being sent by ResumeBroadcast.
+func (m *Manager) isHeld(id string) bool {
+	m.broadcast.mu.Lock()
+	defer m.broadcast.mu.Unlock()
+
+	for _, txs := range [][]heldTx{m.broadcast.txs, m.broadcast.sending} {
+		for _, h := range txs {
+			if h.queuedTx.ID == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nextHeldNonce returns the nonce following the highest nonce reserved
+// by held transactions of the account.
+func (m *Manager) nextHeldNonce(address common.Address) (next uint64, ok bool) {
+	m.broadcast.mu.Lock()
+	defer m.broadcast.mu.Unlock()
+
+	for _, txs := range [][]heldTx{m.broadcast.txs, m.broadcast.sending} {
+		for _, h := range txs {
+			if h.queuedTx.Args.From == address && h.tx.Nonce() >= next {
+				next, ok = h.tx.Nonce()+1, true
+			}
+		}
+	}
+	return next, ok
+}
+
+// removeHeld returns txs without the transaction with the given id.
+func removeHeld(txs []heldTx, id string) []heldTx {
+	for i, h := range txs {
+		if h.queuedTx.ID == id {
+			return append(txs[:i:i], txs[i+1:]...)
+		}
+	}
+	return txs
+}
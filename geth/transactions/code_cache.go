@@ -0,0 +1,61 @@
+package transactions
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+// CodeCheckTTL is how long the result of checking that a called contract has code is reused.
+const CodeCheckTTL = 30 * time.Second
+
+type codeCheck struct {
+	hasCode bool
+	expires time.Time
+}
+
+// codeCache keeps results of checking that contracts have code, so that
+// repeated calls of the same contract don't query the node every time.
+type codeCache struct {
+	mu     sync.Mutex
+	checks map[gethcommon.Address]codeCheck
+}
+
+// reset forgets results of all checks.
+func (c *codeCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks = nil
+}
+
+// hasCode checks if there is code at the address in the latest block.
+// The cache isn't locked while the node is queried.
+func (m *Manager) hasCode(ctx context.Context, address gethcommon.Address) (bool, error) {
+	m.codeCache.mu.Lock()
+	check, ok := m.codeCache.checks[address]
+	m.codeCache.mu.Unlock()
+	if ok && time.Now().Before(check.expires) {
+		return check.hasCode, nil
+	}
+
+	code, err := m.ethTxClient.CodeAt(ctx, address, nil)
+	if err != nil {
+		return false, err
+	}
+
+	m.codeCache.mu.Lock()
+	defer m.codeCache.mu.Unlock()
+	if m.codeCache.checks == nil {
+		m.codeCache.checks = make(map[gethcommon.Address]codeCheck)
+	}
+	for addr, check := range m.codeCache.checks {
+		if !time.Now().Before(check.expires) {
+			delete(m.codeCache.checks, addr)
+		}
+	}
+	m.codeCache.checks[address] = codeCheck{hasCode: len(code) > 0, expires: time.Now().Add(CodeCheckTTL)}
+	return len(code) > 0, nil
+}
@@ -0,0 +1,116 @@
+package transactions
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// DecodedTx contains fields of a signed transaction
+// which is going to be broadcasted.
+type DecodedTx struct {
+	Hash     common.Hash     `json:"hash"`
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Value    *hexutil.Big    `json:"value"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	GasPrice *hexutil.Big    `json:"gasPrice"`
+	Nonce    hexutil.Uint64  `json:"nonce"`
+	ChainID  *hexutil.Big    `json:"chainId,omitempty"`
+	Input    hexutil.Bytes   `json:"input"`
+
+	// Method and Args are set only if transaction is decoded with ABI.
+	Method string        `json:"method,omitempty"`
+	Args   []interface{} `json:"args,omitempty"`
+}
+
+// DecodeRawTransaction decodes RLP encoded signed transaction
+// and recovers its sender.
+func DecodeRawTransaction(raw hexutil.Bytes) (DecodedTx, error) {
+	var decoded DecodedTx
+	if len(raw) > 0 && raw[0] <= 0x7f {
+		return decoded, ErrTypedTxNotSupported
+	}
+
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(raw, tx); err != nil {
+		return decoded, err
+	}
+
+	var signer types.Signer = types.HomesteadSigner{}
+	if tx.Protected() {
+		signer = types.NewEIP155Signer(tx.ChainId())
+		decoded.ChainID = (*hexutil.Big)(tx.ChainId())
+	}
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return decoded, err
+	}
+
+	decoded.Hash = tx.Hash()
+	decoded.From = from
+	decoded.To = tx.To()
+	decoded.Value = (*hexutil.Big)(tx.Value())
+	decoded.Gas = hexutil.Uint64(tx.Gas())
+	decoded.GasPrice = (*hexutil.Big)(tx.GasPrice())
+	decoded.Nonce = hexutil.Uint64(tx.Nonce())
+	decoded.Input = tx.Data()
+	return decoded, nil
+}
+
+// DecodeRawTransactionWithABI decodes RLP encoded signed transaction
+// and its input as a call of contract's method.
+func DecodeRawTransactionWithABI(raw hexutil.Bytes, contract abi.ABI) (DecodedTx, error) {
+	decoded, err := DecodeRawTransaction(raw)
+	if err != nil {
+		return decoded, err
+	}
+	decoded.Method, decoded.Args, err = decodeInput(contract, decoded.Input)
+	return decoded, err
+}
+
+// decodeInput finds the method by its id and unpacks its arguments.
+func decodeInput(contract abi.ABI, input []byte) (string, []interface{}, error) {
+	if len(input) < 4 {
+		return "", nil, ErrUnknownMethod
+	}
+	for _, method := range contract.Methods {
+		if !bytes.Equal(method.Id(), input[:4]) {
+			continue
+		}
+		args, err := unpackArgs(method.Inputs, input[4:])
+		return method.Name, args, err
+	}
+	return "", nil, ErrUnknownMethod
+}
+
+// unpackArgs unpacks arguments into values of types chosen by abi package.
+func unpackArgs(arguments abi.Arguments, data []byte) ([]interface{}, error) {
+	switch len(arguments) {
+	case 0:
+		return nil, nil
+	case 1:
+		var value interface{}
+		if err := arguments.Unpack(&value, data); err != nil {
+			return nil, err
+		}
+		return []interface{}{value}, nil
+	}
+
+	// abi package sets tuple elements through pointers
+	values := make([]interface{}, len(arguments))
+	for i := range values {
+		values[i] = new(interface{})
+	}
+	if err := arguments.Unpack(&values, data); err != nil {
+		return nil, err
+	}
+	for i, v := range values {
+		values[i] = *v.(*interface{})
+	}
+	return values, nil
+}
@@ -0,0 +1,71 @@
+package transactions
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/require"
+)
+
+const transferABI = `[{"name":"transfer","type":"function","inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}]}]`
+
+func signedRawTx(t *testing.T, signer types.Signer, data []byte) (hexutil.Bytes, common.Address, *types.Transaction) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	to := common.HexToAddress("0x0100000000000000000000000000000000000000")
+	tx, err := types.SignTx(types.NewTransaction(7, to, big.NewInt(10), 21000, big.NewInt(20), data), signer, key)
+	require.NoError(t, err)
+	raw, err := rlp.EncodeToBytes(tx)
+	require.NoError(t, err)
+	return raw, crypto.PubkeyToAddress(key.PublicKey), tx
+}
+
+func TestDecodeRawTransaction(t *testing.T) {
+	for _, signer := range []types.Signer{types.HomesteadSigner{}, types.NewEIP155Signer(big.NewInt(3))} {
+		raw, from, tx := signedRawTx(t, signer, []byte{1, 2})
+
+		decoded, err := DecodeRawTransaction(raw)
+		require.NoError(t, err)
+		require.Equal(t, tx.Hash(), decoded.Hash)
+		require.Equal(t, from, decoded.From)
+		require.Equal(t, tx.To(), decoded.To)
+		require.Equal(t, big.NewInt(10), decoded.Value.ToInt())
+		require.Equal(t, hexutil.Uint64(21000), decoded.Gas)
+		require.Equal(t, big.NewInt(20), decoded.GasPrice.ToInt())
+		require.Equal(t, hexutil.Uint64(7), decoded.Nonce)
+		require.Equal(t, hexutil.Bytes{1, 2}, decoded.Input)
+		if tx.Protected() {
+			require.Equal(t, big.NewInt(3), decoded.ChainID.ToInt())
+		} else {
+			require.Nil(t, decoded.ChainID)
+		}
+	}
+
+	_, err := DecodeRawTransaction(hexutil.Bytes{0x02, 0xc0})
+	require.Equal(t, ErrTypedTxNotSupported, err)
+}
+
+func TestDecodeRawTransactionWithABI(t *testing.T) {
+	contract, err := abi.JSON(strings.NewReader(transferABI))
+	require.NoError(t, err)
+	recipient := common.HexToAddress("0x0200000000000000000000000000000000000000")
+	input, err := contract.Pack("transfer", recipient, big.NewInt(5))
+	require.NoError(t, err)
+
+	raw, _, _ := signedRawTx(t, types.HomesteadSigner{}, input)
+	decoded, err := DecodeRawTransactionWithABI(raw, contract)
+	require.NoError(t, err)
+	require.Equal(t, "transfer", decoded.Method)
+	require.Equal(t, []interface{}{recipient, big.NewInt(5)}, decoded.Args)
+
+	raw, _, _ = signedRawTx(t, types.HomesteadSigner{}, []byte{1, 2, 3, 4})
+	_, err = DecodeRawTransactionWithABI(raw, contract)
+	require.Equal(t, ErrUnknownMethod, err)
+}
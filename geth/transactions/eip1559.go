@@ -0,0 +1,55 @@
+package transactions
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// applyEIP1559Fees converts EIP-1559 fees of the transaction to gas price, so that it
+// is sent as a legacy transaction. On chains with base fee, the price is the one the
+// transaction would pay: base fee plus priority fee, capped by MaxFeePerGas. On chains
+// without base fee, it's the suggested gas price capped by MaxFeePerGas, and true is
+// returned, unless such transactions are configured to be refused.
+// Explicit gas price takes precedence over EIP-1559 fees.
+func (m *Manager) applyEIP1559Fees(tx *QueuedTx) (downgraded bool, err error) {
+	args := &tx.Args
+	if args.MaxFeePerGas == nil || args.GasPrice != nil {
+		return false, nil
+	}
+	maxFee := args.MaxFeePerGas.ToInt()
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.rpcCallTimeout)
+	defer cancel()
+	baseFee, err := m.ethTxClient.BlockBaseFee(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var price *big.Int
+	if baseFee != nil {
+		price = new(big.Int).Set(baseFee)
+		if args.MaxPriorityFeePerGas != nil {
+			price.Add(price, args.MaxPriorityFeePerGas.ToInt())
+		}
+	} else {
+		if m.config.RejectEIP1559OnLegacyChains {
+			return false, ErrEIP1559NotSupported
+		}
+		if price, err = m.ethTxClient.SuggestGasPrice(ctx); err != nil {
+			return false, err
+		}
+		downgraded = true
+	}
+	if price.Cmp(maxFee) > 0 {
+		price = maxFee
+	}
+	m.log.Info("EIP-1559 fees are converted to gas price", "id", tx.ID, "gasPrice", price, "baseFee", baseFee)
+
+	args.GasPrice = (*hexutil.Big)(price)
+	tx.derivedPrice = true
+	args.MaxFeePerGas = nil
+	args.MaxPriorityFeePerGas = nil
+	return downgraded, nil
+}
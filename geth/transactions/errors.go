@@ -7,4 +7,67 @@ var (
 	ErrQueuedTxTimedOut = errors.New("transaction sending timed out")
 	//ErrQueuedTxDiscarded - error transaction discarded
 	ErrQueuedTxDiscarded = errors.New("transaction has been discarded")
+	//ErrAccountLoggedOut - error transaction discarded because its account logged out
+	ErrAccountLoggedOut = errors.New("account logged out")
+	//ErrNonceTooLow - error transaction nonce is still too low after retries
+	ErrNonceTooLow = errors.New("nonce too low")
+	//ErrInvalidChainID - error signed transaction is not signed for the current network
+	ErrInvalidChainID = errors.New("transaction is signed for another network")
+	//ErrNonceGap - error transaction refused because of a nonce gap
+	ErrNonceGap = errors.New("nonce gap detected")
+	//ErrQueuedTxSuperseded - error transaction discarded because another one was sent with the same nonce
+	ErrQueuedTxSuperseded = errors.New("transaction has been superseded")
+	//ErrShuttingDown - error transaction is not accepted or discarded because of shutdown
+	ErrShuttingDown = errors.New("transactions manager is shutting down")
+	//ErrQueuedTxEvicted - error transaction removed from a full queue to make room for a new one
+	ErrQueuedTxEvicted = errors.New("transaction has been evicted from a full queue")
+	//ErrTypedTxNotSupported - typed (EIP-2718) transactions can't be decoded
+	ErrTypedTxNotSupported = errors.New("typed transactions are not supported")
+	//ErrUnknownMethod - transaction input doesn't match any method of ABI
+	ErrUnknownMethod = errors.New("no method with such signature in ABI")
+	//ErrEIP1559NotSupported - error transaction has EIP-1559 fees but the chain doesn't support them
+	ErrEIP1559NotSupported = errors.New("EIP-1559 transactions are not supported by the chain")
+	//ErrGasExceedsBlockLimit - error transaction gas is over the limit of the network
+	ErrGasExceedsBlockLimit = errors.New("transaction gas exceeds block gas limit")
+	//ErrNonceAlreadyMined - error another transaction with the same nonce is already mined
+	ErrNonceAlreadyMined = errors.New("transaction with the same nonce is already mined")
+	//ErrNodeNotSynced - error transaction refused while the local node is still syncing
+	ErrNodeNotSynced = errors.New("node is not synced")
+	//ErrSignedTxMismatch - error signed transaction differs from the queued one it is imported for
+	ErrSignedTxMismatch = errors.New("signed transaction doesn't match the queued transaction")
+	//ErrBroadcastHeld - transaction is signed, but held in the queue until broadcasting is resumed
+	ErrBroadcastHeld = errors.New("transaction is held until broadcasting is resumed")
+	//ErrPrecedingTxFailed - error held transaction is not sent because a preceding one of the account failed
+	ErrPrecedingTxFailed = errors.New("preceding held transaction of the account failed")
 )
+
+// TxError is returned when sending of a queued transaction failed.
+// It carries the same error code which is sent with transaction.failed signal.
+type TxError struct {
+	ID   string // ID of the queued transaction
+	Code int    // one of SendTransaction*ErrorCode values
+	Err  error  // underlying error
+}
+
+// NewTxError wraps err returned for transaction with a given id.
+func NewTxError(id string, err error) *TxError {
+	return &TxError{
+		ID:   id,
+		Code: sendTransactionErrorCode(err),
+		Err:  err,
+	}
+}
+
+// Error implements error interface. It returns message of the underlying error.
+func (e *TxError) Error() string {
+	return e.Err.Error()
+}
+
+// Cause returns the underlying error of TxError, so that it can be compared
+// to sentinel errors. Other errors are returned as they are.
+func Cause(err error) error {
+	if txErr, ok := err.(*TxError); ok {
+		return txErr.Err
+	}
+	return err
+}
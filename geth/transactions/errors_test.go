@@ -0,0 +1,24 @@
+package transactions
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxError(t *testing.T) {
+	err := NewTxError("id", ErrQueuedTxDiscarded)
+	require.Equal(t, "id", err.ID)
+	require.Equal(t, SendTransactionDiscardedErrorCode, err.Code)
+	require.EqualError(t, err, ErrQueuedTxDiscarded.Error())
+	require.Equal(t, ErrQueuedTxDiscarded, Cause(err))
+	require.Equal(t, ErrQueuedTxDiscarded, Cause(ErrQueuedTxDiscarded))
+
+	err = NewTxError("id", keystore.ErrDecrypt)
+	require.Equal(t, SendTransactionPasswordErrorCode, err.Code)
+
+	err = NewTxError("id", errors.New("unknown"))
+	require.Equal(t, SendTransactionDefaultErrorCode, err.Code)
+}
@@ -15,6 +15,12 @@ import (
 // EthTransactor provides methods to create transactions for ethereum network.
 type EthTransactor interface {
 	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	BlockGasLimit(ctx context.Context) (uint64, error)
+	BlockBaseFee(ctx context.Context) (*big.Int, error)
+	CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error)
+	TransactionMined(ctx context.Context, hash common.Hash) (bool, error)
+	GasUsed(ctx context.Context, hash common.Hash) (gas uint64, mined bool, err error)
 	ethereum.GasEstimator
 	ethereum.GasPricer
 	ethereum.TransactionSender
@@ -38,6 +44,68 @@ func (ec *EthTxClient) PendingNonceAt(ctx context.Context, account common.Addres
 	return uint64(result), err
 }
 
+// NonceAt returns the account nonce of the given account at the given block.
+// The latest known block is used if blockNumber is nil.
+func (ec *EthTxClient) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	var result hexutil.Uint64
+	err := ec.c.CallContext(ctx, &result, "eth_getTransactionCount", account, toBlockNumArg(blockNumber))
+	return uint64(result), err
+}
+
+// CodeAt returns the contract code of the given account at the given block.
+// The latest known block is used if blockNumber is nil.
+func (ec *EthTxClient) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	var result hexutil.Bytes
+	err := ec.c.CallContext(ctx, &result, "eth_getCode", contract, toBlockNumArg(blockNumber))
+	return result, err
+}
+
+// TransactionMined checks if a receipt of the transaction is available.
+func (ec *EthTxClient) TransactionMined(ctx context.Context, hash common.Hash) (bool, error) {
+	var receipt map[string]interface{}
+	if err := ec.c.CallContext(ctx, &receipt, "eth_getTransactionReceipt", hash); err != nil {
+		return false, err
+	}
+	return receipt != nil, nil
+}
+
+// GasUsed returns gas used by the transaction, if it's mined.
+func (ec *EthTxClient) GasUsed(ctx context.Context, hash common.Hash) (gas uint64, mined bool, err error) {
+	var receipt *struct {
+		GasUsed hexutil.Uint64 `json:"gasUsed"`
+	}
+	if err := ec.c.CallContext(ctx, &receipt, "eth_getTransactionReceipt", hash); err != nil {
+		return 0, false, err
+	}
+	if receipt == nil {
+		return 0, false, nil
+	}
+	return uint64(receipt.GasUsed), true, nil
+}
+
+// BlockGasLimit returns the gas limit of the latest block.
+func (ec *EthTxClient) BlockGasLimit(ctx context.Context) (uint64, error) {
+	var head struct {
+		GasLimit hexutil.Uint64 `json:"gasLimit"`
+	}
+	if err := ec.c.CallContext(ctx, &head, "eth_getBlockByNumber", "latest", false); err != nil {
+		return 0, err
+	}
+	return uint64(head.GasLimit), nil
+}
+
+// BlockBaseFee returns the base fee of the latest block.
+// Nil is returned if the chain doesn't support EIP-1559.
+func (ec *EthTxClient) BlockBaseFee(ctx context.Context) (*big.Int, error) {
+	var head struct {
+		BaseFee *hexutil.Big `json:"baseFeePerGas"`
+	}
+	if err := ec.c.CallContext(ctx, &head, "eth_getBlockByNumber", "latest", false); err != nil {
+		return nil, err
+	}
+	return (*big.Int)(head.BaseFee), nil
+}
+
 // SuggestGasPrice retrieves the currently suggested gas price to allow a timely
 // execution of a transaction.
 func (ec *EthTxClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
@@ -73,6 +141,13 @@ func (ec *EthTxClient) SendTransaction(ctx context.Context, tx *types.Transactio
 	return ec.c.CallContext(ctx, nil, "eth_sendRawTransaction", common.ToHex(data))
 }
 
+func toBlockNumArg(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+	return hexutil.EncodeBig(number)
+}
+
 func toCallArg(msg ethereum.CallMsg) interface{} {
 	arg := map[string]interface{}{
 		"from": msg.From,
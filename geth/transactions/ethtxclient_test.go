@@ -0,0 +1,59 @@
+package transactions
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/geth/account"
+	"github.com/status-im/status-go/geth/params"
+	"github.com/status-im/status-go/geth/rpc"
+	. "github.com/status-im/status-go/t/utils"
+)
+
+func TestEthTxClientCancellation(t *testing.T) {
+	client, err := rpc.NewClient(nil, params.UpstreamRPCConfig{})
+	require.NoError(t, err)
+
+	release := make(chan struct{})
+	defer close(release)
+	slowHandler := func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		<-release
+		return "0x1", nil
+	}
+	for _, method := range []string{"eth_getTransactionCount", "eth_getBlockByNumber", "eth_gasPrice", "eth_estimateGas"} {
+		client.RegisterHandler(method, slowHandler)
+	}
+	ethTxClient := NewEthTxClient(client)
+	from := account.FromAddress(TestConfig.Account1.Address)
+
+	calls := map[string]func(ctx context.Context) error{
+		"PendingNonceAt": func(ctx context.Context) error {
+			_, err := ethTxClient.PendingNonceAt(ctx, from)
+			return err
+		},
+		"BlockGasLimit": func(ctx context.Context) error {
+			_, err := ethTxClient.BlockGasLimit(ctx)
+			return err
+		},
+		"SuggestGasPrice": func(ctx context.Context) error {
+			_, err := ethTxClient.SuggestGasPrice(ctx)
+			return err
+		},
+		"EstimateGas": func(ctx context.Context) error {
+			_, err := ethTxClient.EstimateGas(ctx, ethereum.CallMsg{From: from})
+			return err
+		},
+	}
+	for name, call := range calls {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		start := time.Now()
+		err := call(ctx)
+		cancel()
+		require.Equal(t, context.DeadlineExceeded, err, name)
+		require.True(t, time.Since(start) < time.Second, "%s is not canceled in time", name)
+	}
+}
@@ -63,6 +63,32 @@ func (mr *MockPublicTransactionPoolAPIMockRecorder) GasPrice(arg0 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GasPrice", reflect.TypeOf((*MockPublicTransactionPoolAPI)(nil).GasPrice), arg0)
 }
 
+// GetBlockByNumber mocks base method
+func (m *MockPublicTransactionPoolAPI) GetBlockByNumber(arg0 context.Context, arg1 rpc.BlockNumber, arg2 bool) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetBlockByNumber", arg0, arg1, arg2)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockByNumber indicates an expected call of GetBlockByNumber
+func (mr *MockPublicTransactionPoolAPIMockRecorder) GetBlockByNumber(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockByNumber", reflect.TypeOf((*MockPublicTransactionPoolAPI)(nil).GetBlockByNumber), arg0, arg1, arg2)
+}
+
+// GetCode mocks base method
+func (m *MockPublicTransactionPoolAPI) GetCode(arg0 context.Context, arg1 common.Address, arg2 rpc.BlockNumber) (hexutil.Bytes, error) {
+	ret := m.ctrl.Call(m, "GetCode", arg0, arg1, arg2)
+	ret0, _ := ret[0].(hexutil.Bytes)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCode indicates an expected call of GetCode
+func (mr *MockPublicTransactionPoolAPIMockRecorder) GetCode(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCode", reflect.TypeOf((*MockPublicTransactionPoolAPI)(nil).GetCode), arg0, arg1, arg2)
+}
+
 // GetTransactionCount mocks base method
 func (m *MockPublicTransactionPoolAPI) GetTransactionCount(arg0 context.Context, arg1 common.Address, arg2 rpc.BlockNumber) (*hexutil.Uint64, error) {
 	ret := m.ctrl.Call(m, "GetTransactionCount", arg0, arg1, arg2)
@@ -76,6 +102,19 @@ func (mr *MockPublicTransactionPoolAPIMockRecorder) GetTransactionCount(arg0, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionCount", reflect.TypeOf((*MockPublicTransactionPoolAPI)(nil).GetTransactionCount), arg0, arg1, arg2)
 }
 
+// GetTransactionReceipt mocks base method
+func (m *MockPublicTransactionPoolAPI) GetTransactionReceipt(arg0 context.Context, arg1 common.Hash) (map[string]interface{}, error) {
+	ret := m.ctrl.Call(m, "GetTransactionReceipt", arg0, arg1)
+	ret0, _ := ret[0].(map[string]interface{})
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionReceipt indicates an expected call of GetTransactionReceipt
+func (mr *MockPublicTransactionPoolAPIMockRecorder) GetTransactionReceipt(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionReceipt", reflect.TypeOf((*MockPublicTransactionPoolAPI)(nil).GetTransactionReceipt), arg0, arg1)
+}
+
 // SendRawTransaction mocks base method
 func (m *MockPublicTransactionPoolAPI) SendRawTransaction(arg0 context.Context, arg1 hexutil.Bytes) (common.Hash, error) {
 	ret := m.ctrl.Call(m, "SendRawTransaction", arg0, arg1)
@@ -0,0 +1,90 @@
+package transactions
+
+import (
+	"context"
+	"math/big"
+
+	ethereum "github.com/ethereum/go-ethereum"
+)
+
+// weiPerToken is a number of wei in a whole native token.
+var weiPerToken = new(big.Float).SetInt(big.NewInt(1e18))
+
+// FiatPriceProvider provides price of the native token, e.g. ether, in fiat currency.
+// It is implemented by the app, no prices are fetched by default.
+type FiatPriceProvider interface {
+	// NativeTokenPrice returns price of a whole native token of the given network.
+	NativeTokenPrice(ctx context.Context, networkID uint64) (float64, error)
+}
+
+// SetFiatPriceProvider sets provider used to enforce TransactionsConfig.MaxFiatFee.
+// Passing nil disables the cap.
+func (m *Manager) SetFiatPriceProvider(provider FiatPriceProvider) {
+	m.fiatPriceMx.Lock()
+	defer m.fiatPriceMx.Unlock()
+
+	m.fiatPrice = provider
+}
+
+// isFeeOverFiatCap returns true if estimated fee of transaction is over the fiat cap.
+// If price, gas price or gas are not available, the cap is not enforced. The check
+// runs before transaction.queued signal is sent, so it's limited by warningsTimeout.
+func (m *Manager) isFeeOverFiatCap(tx *QueuedTx) bool {
+	m.fiatPriceMx.RLock()
+	provider := m.fiatPrice
+	m.fiatPriceMx.RUnlock()
+	if provider == nil || m.config.MaxFiatFee == 0 {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.warningsTimeout)
+	defer cancel()
+	fee, err := m.estimateFee(ctx, tx)
+	if err != nil {
+		m.log.Warn("failed to estimate transaction fee", "id", tx.ID, "err", err)
+		return false
+	}
+	price, err := provider.NativeTokenPrice(ctx, m.network())
+	if err != nil {
+		m.log.Warn("failed to get native token price", "err", err)
+		return false
+	}
+
+	tokens := new(big.Float).Quo(new(big.Float).SetInt(fee), weiPerToken)
+	fiatFee, _ := tokens.Mul(tokens, big.NewFloat(price)).Float64()
+	return fiatFee > m.config.MaxFiatFee
+}
+
+// estimateFee returns the maximum fee, in wei, transaction may pay.
+// Gas and gas price set by the client are used as they are.
+func (m *Manager) estimateFee(ctx context.Context, tx *QueuedTx) (*big.Int, error) {
+	args := tx.Args
+	gasPrice := (*big.Int)(args.GasPrice)
+	if args.MaxFeePerGas != nil {
+		gasPrice = (*big.Int)(args.MaxFeePerGas)
+	}
+	if gasPrice == nil {
+		var err error
+		if gasPrice, err = m.ethTxClient.SuggestGasPrice(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var gas uint64
+	if args.Gas != nil {
+		gas = uint64(*args.Gas)
+	} else {
+		var err error
+		gas, err = m.ethTxClient.EstimateGas(ctx, ethereum.CallMsg{
+			From:     args.From,
+			To:       args.To,
+			GasPrice: gasPrice,
+			Value:    (*big.Int)(args.Value),
+			Data:     args.GetInput(),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gas)), nil
+}
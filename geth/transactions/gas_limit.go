@@ -0,0 +1,62 @@
+package transactions
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BlockGasLimitTTL is how long the gas limit of the latest block is reused.
+const BlockGasLimitTTL = time.Minute
+
+// gasLimitCache keeps the gas limit of the latest block, which changes slowly.
+type gasLimitCache struct {
+	mu      sync.Mutex
+	limit   uint64
+	expires time.Time
+}
+
+// reset forgets the cached gas limit.
+func (c *gasLimitCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.expires = time.Time{}
+}
+
+// blockGasLimit returns the gas limit of the latest block. It is fetched on first use
+// and then reused for BlockGasLimitTTL.
+func (m *Manager) blockGasLimit() (uint64, error) {
+	m.gasLimit.mu.Lock()
+	defer m.gasLimit.mu.Unlock()
+
+	if time.Now().Before(m.gasLimit.expires) {
+		return m.gasLimit.limit, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), m.rpcCallTimeout)
+	defer cancel()
+	limit, err := m.ethTxClient.BlockGasLimit(ctx)
+	if err != nil {
+		return 0, err
+	}
+	m.gasLimit.limit = limit
+	m.gasLimit.expires = time.Now().Add(BlockGasLimitTTL)
+	return limit, nil
+}
+
+// checkGasLimit refuses gas over the configured limit of the network,
+// or over the latest block gas limit if the network has no limit configured.
+func (m *Manager) checkGasLimit(gas uint64) error {
+	limit, ok := m.config.MaxGasLimits[m.network()]
+	if !ok {
+		var err error
+		if limit, err = m.blockGasLimit(); err != nil {
+			return err
+		}
+	}
+	if gas > limit {
+		m.log.Warn("transaction gas exceeds the limit", "gas", gas, "limit", limit)
+		return ErrGasExceedsBlockLimit
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package transactions
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// gasPriceStore keeps the last gas price chosen for each account.
+// Prices are persisted if the store has a file.
+type gasPriceStore struct {
+	mu     sync.RWMutex // guards fields below
+	path   string
+	prices map[common.Address]*hexutil.Big
+}
+
+func newGasPriceStore() *gasPriceStore {
+	return &gasPriceStore{prices: make(map[common.Address]*hexutil.Big)}
+}
+
+// load replaces prices with those persisted in the given file,
+// which is used to persist them further. Missing file is not an error.
+func (s *gasPriceStore) load(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.path = path
+	s.prices = make(map[common.Address]*hexutil.Big)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &s.prices)
+}
+
+func (s *gasPriceStore) get(address common.Address) (*hexutil.Big, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	price, ok := s.prices[address]
+	return price, ok
+}
+
+// set stores the gas price of the account and persists all prices.
+// The file is replaced atomically, so a crash doesn't leave it truncated.
+func (s *gasPriceStore) set(address common.Address, price *hexutil.Big) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prices[address] = price
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(s.prices)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data, 0600)
+}
+
+// writeFileAtomic writes data to a temporary file in the same directory
+// and renames it to path.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) (err error) {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()           // nolint: errcheck
+			os.Remove(f.Name()) // nolint: errcheck
+		}
+	}()
+	if _, err = f.Write(data); err != nil {
+		return err
+	}
+	if err = f.Chmod(perm); err != nil {
+		return err
+	}
+	if err = f.Sync(); err != nil {
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
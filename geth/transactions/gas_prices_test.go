@@ -0,0 +1,41 @@
+package transactions
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGasPriceStorePersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gas_prices")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) //nolint: errcheck
+	path := filepath.Join(dir, "gas_prices.json")
+	address := common.HexToAddress("0x01")
+
+	store := newGasPriceStore()
+	require.NoError(t, store.load(path))
+	_, ok := store.get(address)
+	require.False(t, ok)
+	require.NoError(t, store.set(address, (*hexutil.Big)(big.NewInt(20))))
+
+	// prices survive restart
+	store = newGasPriceStore()
+	require.NoError(t, store.load(path))
+	price, ok := store.get(address)
+	require.True(t, ok)
+	require.Equal(t, big.NewInt(20), price.ToInt())
+
+	// file is replaced without leaving temporary files
+	require.NoError(t, store.set(address, (*hexutil.Big)(big.NewInt(30))))
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, os.FileMode(0600), files[0].Mode().Perm())
+}
@@ -0,0 +1,150 @@
+package transactions
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// GasUsagePollInterval is how often a receipt of a sent contract call is checked
+	// in order to learn how much gas it used.
+	GasUsagePollInterval = 15 * time.Second
+
+	// GasUsagePollTimeout is how long a receipt of a sent contract call is waited for.
+	GasUsagePollTimeout = 10 * time.Minute
+)
+
+// gasUsageKey identifies a method of a contract.
+type gasUsageKey struct {
+	to       gethcommon.Address
+	selector [4]byte
+}
+
+// gasUsageKeyOf returns a key of contract method called by the transaction.
+// False is returned if the transaction is not a contract call.
+func gasUsageKeyOf(args SendTxArgs) (key gasUsageKey, ok bool) {
+	input := args.GetInput()
+	if args.To == nil || len(input) < len(key.selector) {
+		return key, false
+	}
+	key.to = *args.To
+	copy(key.selector[:], input)
+	return key, true
+}
+
+// gasUsageCache keeps gas used by the latest calls of contract methods.
+// When it's full, the method recorded first is forgotten.
+// Nil cache is valid and doesn't keep anything.
+type gasUsageCache struct {
+	mu    sync.Mutex
+	size  int
+	gas   map[gasUsageKey]uint64
+	order []gasUsageKey // in order of recording, oldest first
+}
+
+// newGasUsageCache returns a cache of the given size, or nil if size is not positive.
+func newGasUsageCache(size int) *gasUsageCache {
+	if size <= 0 {
+		return nil
+	}
+	return &gasUsageCache{
+		size: size,
+		gas:  make(map[gasUsageKey]uint64, size),
+	}
+}
+
+func (c *gasUsageCache) record(key gasUsageKey, gas uint64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.gas[key]; !ok {
+		if len(c.order) == c.size {
+			delete(c.gas, c.order[0])
+			c.order = c.order[1:]
+		}
+		c.order = append(c.order, key)
+	}
+	c.gas[key] = gas
+}
+
+func (c *gasUsageCache) get(key gasUsageKey) (uint64, bool) {
+	if c == nil {
+		return 0, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	gas, ok := c.gas[key]
+	return gas, ok
+}
+
+func (c *gasUsageCache) clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gas = make(map[gasUsageKey]uint64, c.size)
+	c.order = nil
+}
+
+// ClearGasUsageCache forgets gas used by previous contract calls.
+func (m *Manager) ClearGasUsageCache() {
+	m.gasUsage.clear()
+}
+
+// reconcileGasEstimate raises estimated gas of a contract call to the gas used by
+// the previous call of the same method, as estimation may be off if contract state
+// changes before the transaction is mined.
+func (m *Manager) reconcileGasEstimate(queuedTx *QueuedTx, estimated uint64) uint64 {
+	key, ok := gasUsageKeyOf(queuedTx.Args)
+	if !ok {
+		return estimated
+	}
+	if used, ok := m.gasUsage.get(key); ok && used > estimated {
+		m.log.Info("estimated gas is raised to previously used gas", "id", queuedTx.ID, "estimated", estimated, "used", used)
+		return used
+	}
+	return estimated
+}
+
+// learnGasUsage waits for a receipt of the sent contract call and records gas it used.
+// It returns early if stopped is closed.
+func (m *Manager) learnGasUsage(queuedTx *QueuedTx, hash gethcommon.Hash, stopped <-chan struct{}) {
+	key, ok := gasUsageKeyOf(queuedTx.Args)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), GasUsagePollTimeout)
+	defer cancel()
+	ticker := time.NewTicker(GasUsagePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-stopped:
+			return
+		case <-ctx.Done():
+			m.log.Debug("receipt is not available, gas usage is not recorded", "hash", hash)
+			return
+		}
+
+		callCtx, callCancel := context.WithTimeout(ctx, m.rpcCallTimeout)
+		gas, mined, err := m.ethTxClient.GasUsed(callCtx, hash)
+		callCancel()
+		if err != nil {
+			m.log.Debug("failed to get transaction receipt", "hash", hash, "err", err)
+			continue
+		}
+		if mined {
+			m.gasUsage.record(key, gas)
+			return
+		}
+	}
+}
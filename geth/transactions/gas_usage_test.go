@@ -0,0 +1,53 @@
+package transactions
+
+import (
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGasUsageKey(t *testing.T) {
+	to := gethcommon.HexToAddress("0x01")
+
+	_, ok := gasUsageKeyOf(SendTxArgs{To: &to})
+	require.False(t, ok, "transfer is not a contract call")
+	_, ok = gasUsageKeyOf(SendTxArgs{Input: hexutil.Bytes{1, 2, 3, 4}})
+	require.False(t, ok, "contract creation is not a contract call")
+
+	key, ok := gasUsageKeyOf(SendTxArgs{To: &to, Input: hexutil.Bytes{1, 2, 3, 4, 5}})
+	require.True(t, ok)
+	require.Equal(t, gasUsageKey{to: to, selector: [4]byte{1, 2, 3, 4}}, key)
+}
+
+func TestGasUsageCache(t *testing.T) {
+	first := gasUsageKey{selector: [4]byte{1}}
+	second := gasUsageKey{selector: [4]byte{2}}
+	third := gasUsageKey{selector: [4]byte{3}}
+
+	c := newGasUsageCache(2)
+	c.record(first, 100)
+	c.record(second, 200)
+	c.record(first, 150)
+	gas, ok := c.get(first)
+	require.True(t, ok)
+	require.Equal(t, uint64(150), gas)
+
+	// the method recorded first is forgotten
+	c.record(third, 300)
+	_, ok = c.get(first)
+	require.False(t, ok)
+	_, ok = c.get(second)
+	require.True(t, ok)
+
+	c.clear()
+	_, ok = c.get(third)
+	require.False(t, ok)
+
+	// disabled cache doesn't keep anything
+	c = newGasUsageCache(0)
+	c.record(first, 100)
+	_, ok = c.get(first)
+	require.False(t, ok)
+}
@@ -1,7 +1,10 @@
 package transactions
 
 import (
+	"time"
+
 	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/status-im/status-go/geth/signal"
 )
 
@@ -10,6 +13,26 @@ const (
 	EventTransactionQueued = "transaction.queued"
 	// EventTransactionFailed is triggered when send transaction request fails
 	EventTransactionFailed = "transaction.failed"
+	// EventNonceGap is triggered when a transaction is completed while previously sent
+	// transactions of the account are missing, i.e. local nonce is ahead of the pending one
+	EventNonceGap = "transaction.nonce_gap"
+	// EventExternalSign is triggered when transaction is passed to an external signer
+	// and user interaction with it, e.g. confirmation on a device, is required
+	EventExternalSign = "transaction.external_sign"
+	// EventQueueLength is triggered when number of queued transactions changes
+	EventQueueLength = "transaction.queue_length"
+	// EventTransactionEvicted is triggered when send transaction request is dropped
+	// to make room for another one in a full queue
+	EventTransactionEvicted = "transaction.evicted"
+	// EventHighValueTransactionQueued is triggered instead of EventTransactionQueued when
+	// transaction value is over the configured limits, or its fee is over the fiat cap, and additional confirmation is required
+	EventHighValueTransactionQueued = "transaction.high_value_queued"
+	// EventTransactionDone is triggered when send transaction request leaves the queue,
+	// whether it was completed, discarded, timed out or evicted
+	EventTransactionDone = "transaction.done"
+	// EventBroadcastHeld is triggered when transaction is signed while broadcasting is paused,
+	// it is sent to the network once broadcasting is resumed
+	EventBroadcastHeld = "transaction.signed_pending_broadcast"
 )
 
 const (
@@ -23,13 +46,16 @@ const (
 	SendTransactionTimeoutErrorCode
 	// SendTransactionDiscardedErrorCode is sent when tx was discarded.
 	SendTransactionDiscardedErrorCode
+	// SendTransactionSupersededErrorCode is sent when tx was superseded by another one with the same nonce.
+	SendTransactionSupersededErrorCode
 )
 
 var txReturnCodes = map[error]int{
-	nil:                  SendTransactionNoErrorCode,
-	keystore.ErrDecrypt:  SendTransactionPasswordErrorCode,
-	ErrQueuedTxTimedOut:  SendTransactionTimeoutErrorCode,
-	ErrQueuedTxDiscarded: SendTransactionDiscardedErrorCode,
+	nil:                   SendTransactionNoErrorCode,
+	keystore.ErrDecrypt:   SendTransactionPasswordErrorCode,
+	ErrQueuedTxTimedOut:   SendTransactionTimeoutErrorCode,
+	ErrQueuedTxDiscarded:  SendTransactionDiscardedErrorCode,
+	ErrQueuedTxSuperseded: SendTransactionSupersededErrorCode,
 }
 
 // SendTransactionEvent is a signal sent on a send transaction request
@@ -37,6 +63,7 @@ type SendTransactionEvent struct {
 	ID        string     `json:"id"`
 	Args      SendTxArgs `json:"args"`
 	MessageID string     `json:"message_id"`
+	Warnings  []string   `json:"warnings,omitempty"`
 }
 
 // NotifyOnEnqueue returns handler that processes incoming tx queue requests
@@ -47,6 +74,20 @@ func NotifyOnEnqueue(queuedTx *QueuedTx) {
 			ID:        queuedTx.ID,
 			Args:      queuedTx.Args,
 			MessageID: messageIDFromContext(queuedTx.Context),
+			Warnings:  queuedTx.Warnings,
+		},
+	})
+}
+
+// NotifyOnHighValueEnqueue sends a notification that transaction over the value limits is queued
+func NotifyOnHighValueEnqueue(queuedTx *QueuedTx) {
+	signal.Send(signal.Envelope{
+		Type: EventHighValueTransactionQueued,
+		Event: SendTransactionEvent{
+			ID:        queuedTx.ID,
+			Args:      queuedTx.Args,
+			MessageID: messageIDFromContext(queuedTx.Context),
+			Warnings:  queuedTx.Warnings,
 		},
 	})
 }
@@ -88,3 +129,119 @@ func sendTransactionErrorCode(err error) int {
 	}
 	return SendTxDefaultErrorCode
 }
+
+// NonceGapEvent is a signal sent when a nonce gap is detected.
+// Status has pending and local nonces only.
+type NonceGapEvent struct {
+	Address common.Address `json:"address"`
+	Status  NonceStatus    `json:"status"`
+}
+
+// NotifyOnNonceGap sends a notification about a nonce gap of the given account
+func NotifyOnNonceGap(address common.Address, status NonceStatus) {
+	signal.Send(signal.Envelope{
+		Type: EventNonceGap,
+		Event: NonceGapEvent{
+			Address: address,
+			Status:  status,
+		},
+	})
+}
+
+// ExternalSignEvent is a signal sent when transaction is passed to an external signer
+type ExternalSignEvent struct {
+	ID        string         `json:"id"`
+	Address   common.Address `json:"address"`
+	MessageID string         `json:"message_id"`
+}
+
+// NotifyOnExternalSign sends a notification that transaction awaits external signing
+func NotifyOnExternalSign(queuedTx *QueuedTx) {
+	signal.Send(signal.Envelope{
+		Type: EventExternalSign,
+		Event: ExternalSignEvent{
+			ID:        queuedTx.ID,
+			Address:   queuedTx.Args.From,
+			MessageID: messageIDFromContext(queuedTx.Context),
+		},
+	})
+}
+
+// QueueLengthEvent is a signal sent when number of queued transactions changes
+type QueueLengthEvent struct {
+	Count int `json:"count"`
+}
+
+// NotifyOnQueueLength sends a notification with the number of queued transactions
+func NotifyOnQueueLength(count int) {
+	signal.Send(signal.Envelope{
+		Type:  EventQueueLength,
+		Event: QueueLengthEvent{Count: count},
+	})
+}
+
+// TransactionEvictedEvent is a signal sent when transaction is evicted from a full queue
+type TransactionEvictedEvent struct {
+	ID        string `json:"id"`
+	MessageID string `json:"message_id"`
+	Reason    string `json:"reason"`
+}
+
+// NotifyOnEvict sends a notification that transaction is dropped from a full queue
+func NotifyOnEvict(queuedTx *QueuedTx) {
+	signal.Send(signal.Envelope{
+		Type: EventTransactionEvicted,
+		Event: TransactionEvictedEvent{
+			ID:        queuedTx.ID,
+			MessageID: messageIDFromContext(queuedTx.Context),
+			Reason:    ErrQueuedTxEvicted.Error(),
+		},
+	})
+}
+
+// TransactionDoneEvent is a signal sent when transaction leaves the queue
+type TransactionDoneEvent struct {
+	ID           string      `json:"id"`
+	MessageID    string      `json:"message_id"`
+	Hash         common.Hash `json:"hash"`
+	ErrorMessage string      `json:"error_message,omitempty"`
+	WaitTime     int64       `json:"wait_time"` // milliseconds
+}
+
+// NotifyOnDone sends a notification with the outcome of transaction and how long it was queued
+func NotifyOnDone(queuedTx *QueuedTx, result Result) {
+	event := TransactionDoneEvent{
+		ID:        queuedTx.ID,
+		MessageID: messageIDFromContext(queuedTx.Context),
+		Hash:      result.Hash,
+		WaitTime:  int64(result.WaitTime / time.Millisecond),
+	}
+	if result.Error != nil {
+		event.ErrorMessage = result.Error.Error()
+	}
+	signal.Send(signal.Envelope{
+		Type:  EventTransactionDone,
+		Event: event,
+	})
+}
+
+// BroadcastHeldEvent is a signal sent when signed transaction waits for broadcasting to be resumed
+type BroadcastHeldEvent struct {
+	ID    string         `json:"id"`
+	Hash  common.Hash    `json:"hash"`
+	From  common.Address `json:"from"`
+	Nonce uint64         `json:"nonce"`
+}
+
+// NotifyOnBroadcastHeld sends a notification that signed transaction is held until broadcasting is resumed
+func NotifyOnBroadcastHeld(queuedTx *QueuedTx, hash common.Hash, nonce uint64) {
+	signal.Send(signal.Envelope{
+		Type: EventBroadcastHeld,
+		Event: BroadcastHeldEvent{
+			ID:    queuedTx.ID,
+			Hash:  hash,
+			From:  queuedTx.Args.From,
+			Nonce: nonce,
+		},
+	})
+}
@@ -2,6 +2,7 @@ package transactions
 
 import (
 	"errors"
+	"sort"
 	"sync"
 	"time"
 
@@ -42,6 +43,10 @@ type TxQueue struct {
 	mu           sync.RWMutex // to guard transactions map
 	transactions map[string]*QueuedTx
 	inprogress   map[string]empty
+	seq          uint64                  // incremented for every enqueued transaction
+	countChanged func()                  // invoked whenever number of transactions changes
+	evicted      func(*QueuedTx)         // invoked when transaction is evicted to make room for another one
+	finished     func(*QueuedTx, Result) // invoked when transaction is removed with a result
 
 	// TODO(dshulyak) research why eviction is done in separate goroutine
 	evictableIDs  chan string
@@ -96,12 +101,39 @@ func (q *TxQueue) Stop() {
 	q.log.Info("finally stopped transaction queue")
 }
 
+// OnCountChange sets handler invoked whenever number of queued transactions changes.
+// Handler is invoked with the queue locked, so it must not block or use the queue.
+// It is not thread safe and must be called only before queue is started.
+func (q *TxQueue) OnCountChange(handler func()) {
+	q.countChanged = handler
+}
+
+// OnEvict sets handler invoked when a transaction is evicted from a full queue.
+// It is not thread safe and must be called only before queue is started.
+func (q *TxQueue) OnEvict(handler func(*QueuedTx)) {
+	q.evicted = handler
+}
+
+// OnDone sets handler invoked when a transaction is completed, discarded, timed out or evicted.
+// Handler is invoked after the queue is unlocked, so it may use the queue.
+// It is not thread safe and must be called only before queue is started.
+func (q *TxQueue) OnDone(handler func(*QueuedTx, Result)) {
+	q.finished = handler
+}
+
+// notifyCountChanged invokes count change handler, if it is set.
+func (q *TxQueue) notifyCountChanged() {
+	if q.countChanged != nil {
+		q.countChanged()
+	}
+}
+
 // evictionLoop frees up queue to accommodate another transaction item
 func (q *TxQueue) evictionLoop() {
 	defer haltOnPanic()
 	evict := func() {
 		if q.Count() >= DefaultTxQueueCap { // eviction is required to accommodate another/last item
-			q.Remove(<-q.evictableIDs)
+			q.evict(<-q.evictableIDs)
 		}
 	}
 
@@ -119,6 +151,35 @@ func (q *TxQueue) evictionLoop() {
 	}
 }
 
+// evict removes transaction to make room for another one. Transaction
+// is returned with ErrQueuedTxEvicted, unless it is being completed.
+func (q *TxQueue) evict(id string) {
+	q.mu.Lock()
+	tx, ok := q.transactions[id]
+	if !ok {
+		q.mu.Unlock()
+		return
+	}
+	_, inprogress := q.inprogress[id]
+	var (
+		result   Result
+		finished bool
+	)
+	if inprogress {
+		q.remove(id)
+	} else {
+		result, finished = q.done(tx, gethcommon.Hash{}, ErrQueuedTxEvicted)
+	}
+	q.mu.Unlock()
+
+	if finished {
+		q.notifyFinished(tx, result)
+	}
+	if !inprogress && q.evicted != nil {
+		q.evicted(tx)
+	}
+}
+
 // Reset is to be used in tests only, as it simply creates new transaction map, w/o any cleanup of the previous one
 func (q *TxQueue) Reset() {
 	q.mu.Lock()
@@ -127,6 +188,7 @@ func (q *TxQueue) Reset() {
 	q.transactions = make(map[string]*QueuedTx)
 	q.evictableIDs = make(chan string, DefaultTxQueueCap)
 	q.inprogress = make(map[string]empty)
+	q.notifyCountChanged()
 }
 
 // Enqueue enqueues incoming transaction
@@ -146,7 +208,11 @@ func (q *TxQueue) Enqueue(tx *QueuedTx) error {
 	q.log.Debug("notified eviction loop")
 
 	q.mu.Lock()
+	q.seq++
+	tx.seq = q.seq
+	tx.queuedAt = time.Now()
 	q.transactions[tx.ID] = tx
+	q.notifyCountChanged()
 	q.mu.Unlock()
 
 	// notify handler
@@ -187,34 +253,79 @@ func (q *TxQueue) Remove(id string) {
 }
 
 func (q *TxQueue) remove(id string) {
+	if _, ok := q.transactions[id]; !ok {
+		return
+	}
 	delete(q.transactions, id)
 	delete(q.inprogress, id)
+	q.notifyCountChanged()
 }
 
 // Done removes transaction from queue if no error or error is not transient
 // and notify subscribers
 func (q *TxQueue) Done(id string, hash gethcommon.Hash, err error) error {
 	q.mu.Lock()
-	defer q.mu.Unlock()
 	tx, ok := q.transactions[id]
 	if !ok {
+		q.mu.Unlock()
 		return ErrQueuedTxIDNotFound
 	}
-	q.done(tx, hash, err)
+	result, finished := q.done(tx, hash, err)
+	q.mu.Unlock()
+
+	if finished {
+		q.notifyFinished(tx, result)
+	}
 	return nil
 }
 
-func (q *TxQueue) done(tx *QueuedTx, hash gethcommon.Hash, err error) {
-	delete(q.inprogress, tx.ID)
+// Fail removes transaction from queue with the given error, even if the error
+// is transient, and notify subscribers
+func (q *TxQueue) Fail(id string, err error) error {
+	q.mu.Lock()
+	tx, ok := q.transactions[id]
+	if !ok {
+		q.mu.Unlock()
+		return ErrQueuedTxIDNotFound
+	}
+	result := q.finish(tx, gethcommon.Hash{}, err)
+	q.mu.Unlock()
+
+	q.notifyFinished(tx, result)
+	return nil
+}
+
+// done sends the result of transaction and removes it from the queue, unless the
+// error is transient. It must be called with the queue locked and returns true if
+// the transaction is finished, so that the caller invokes notifyFinished after
+// unlocking the queue.
+func (q *TxQueue) done(tx *QueuedTx, hash gethcommon.Hash, err error) (Result, bool) {
 	// hash is updated only if err is nil, but transaction is not removed from a queue
-	if err == nil {
-		q.transactions[tx.ID].Result <- Result{Hash: hash, Error: err}
-		q.remove(tx.ID)
-		return
+	if err != nil {
+		if _, transient := transientErrs[err.Error()]; transient {
+			delete(q.inprogress, tx.ID)
+			return Result{}, false
+		}
+	}
+	return q.finish(tx, hash, err), true
+}
+
+// finish sends the result of transaction and removes it from the queue.
+// It must be called with the queue locked.
+func (q *TxQueue) finish(tx *QueuedTx, hash gethcommon.Hash, err error) Result {
+	if err != nil {
+		hash = gethcommon.Hash{}
 	}
-	if _, transient := transientErrs[err.Error()]; !transient {
-		q.transactions[tx.ID].Result <- Result{Error: err}
-		q.remove(tx.ID)
+	result := Result{Hash: hash, Error: err, WaitTime: time.Since(tx.queuedAt)}
+	q.transactions[tx.ID].Result <- result
+	q.remove(tx.ID)
+	return result
+}
+
+// notifyFinished invokes done handler, if it is set.
+func (q *TxQueue) notifyFinished(tx *QueuedTx, result Result) {
+	if q.finished != nil {
+		q.finished(tx, result)
 	}
 }
 
@@ -225,6 +336,61 @@ func (q *TxQueue) Count() int {
 	return len(q.transactions)
 }
 
+// Transactions returns a snapshot of currently queued transactions ordered by priority
+func (q *TxQueue) Transactions() []*QueuedTx {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	txs := make([]*QueuedTx, 0, len(q.transactions))
+	for _, tx := range q.transactions {
+		txs = append(txs, tx)
+	}
+	sortByPriority(txs)
+	return txs
+}
+
+// SortByPriority orders given transaction identifiers by priority of
+// queued transactions. Identifiers which are not in the queue go last,
+// in the same order as given.
+func (q *TxQueue) SortByPriority(ids []string) []string {
+	q.mu.RLock()
+	txs := make([]*QueuedTx, 0, len(ids))
+	var unknown []string
+	for _, id := range ids {
+		if tx, ok := q.transactions[id]; ok {
+			txs = append(txs, tx)
+		} else {
+			unknown = append(unknown, id)
+		}
+	}
+	q.mu.RUnlock()
+
+	sortByPriority(txs)
+	sorted := make([]string, 0, len(ids))
+	for _, tx := range txs {
+		sorted = append(sorted, tx.ID)
+	}
+	return append(sorted, unknown...)
+}
+
+// sortByPriority orders transactions by priority, higher first.
+// Transactions of the same priority are kept in FIFO order.
+func sortByPriority(txs []*QueuedTx) {
+	sort.Slice(txs, func(i, j int) bool {
+		if txs[i].Args.Priority != txs[j].Args.Priority {
+			return txs[i].Args.Priority > txs[j].Args.Priority
+		}
+		return txs[i].seq < txs[j].seq
+	})
+}
+
+// IsInprogress checks whether transaction with a given identifier is being completed
+func (q *TxQueue) IsInprogress(id string) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	_, ok := q.inprogress[id]
+	return ok
+}
+
 // Has checks whether transaction with a given identifier exists in queue
 func (q *TxQueue) Has(id string) bool {
 	q.mu.RLock()
@@ -0,0 +1,59 @@
+package transactions
+
+import (
+	"sync"
+	"time"
+)
+
+// QueueLengthDebounce is a delay before the queue length signal is sent,
+// so that rapid changes (e.g. batch operations) result in a single signal.
+const QueueLengthDebounce = 100 * time.Millisecond
+
+// queueLengthNotifier sends the number of queued transactions
+// when it changes, at most once per debounce delay.
+type queueLengthNotifier struct {
+	mu        sync.Mutex // guards fields below
+	scheduled bool
+	last      int
+
+	delay time.Duration
+	count func() int
+	send  func(count int)
+}
+
+func newQueueLengthNotifier(delay time.Duration, count func() int, send func(count int)) *queueLengthNotifier {
+	return &queueLengthNotifier{
+		delay: delay,
+		count: count,
+		send:  send,
+	}
+}
+
+// changed schedules sending of the queue length, unless it is already scheduled.
+func (n *queueLengthNotifier) changed() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.scheduled {
+		return
+	}
+	n.scheduled = true
+	time.AfterFunc(n.delay, n.notify)
+}
+
+// notify sends the current queue length if it differs from the last sent one.
+func (n *queueLengthNotifier) notify() {
+	n.mu.Lock()
+	n.scheduled = false
+	n.mu.Unlock()
+
+	count := n.count()
+	n.mu.Lock()
+	if count == n.last {
+		n.mu.Unlock()
+		return
+	}
+	n.last = count
+	n.mu.Unlock()
+
+	n.send(count)
+}
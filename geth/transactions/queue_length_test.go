@@ -0,0 +1,54 @@
+package transactions
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueLengthNotifier(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		count int
+		sent  []int
+	)
+	notifier := newQueueLengthNotifier(20*time.Millisecond, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return count
+	}, func(count int) {
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, count)
+	})
+	change := func(delta int) {
+		mu.Lock()
+		count += delta
+		mu.Unlock()
+		notifier.changed()
+	}
+	sentCounts := func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int(nil), sent...)
+	}
+
+	// rapid changes result in a single signal
+	for i := 0; i < 5; i++ {
+		change(1)
+	}
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, []int{5}, sentCounts())
+
+	// no signal if count is back to the last sent one
+	change(1)
+	change(-1)
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, []int{5}, sentCounts())
+
+	change(-2)
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, []int{5, 3}, sentCounts())
+}
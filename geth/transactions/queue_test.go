@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	gethcommon "github.com/ethereum/go-ethereum/common"
@@ -114,7 +115,47 @@ func (s QueueTestSuite) TestMultipleDone() {
 	s.Equal(ErrQueuedTxIDNotFound, s.queue.Done(tx.ID, hash, errors.New("timeout")))
 }
 
+func (s *QueueTestSuite) TestDoneWaitTime() {
+	var finished []Result
+	s.queue.OnDone(func(_ *QueuedTx, rst Result) {
+		finished = append(finished, rst)
+	})
+
+	tx := Create(context.Background(), SendTxArgs{})
+	s.NoError(s.queue.Enqueue(tx))
+	time.Sleep(10 * time.Millisecond)
+	// transient errors keep transaction in the queue
+	s.NoError(s.queue.Done(tx.ID, gethcommon.Hash{}, keystore.ErrDecrypt))
+	s.Empty(finished)
+
+	s.NoError(s.queue.Done(tx.ID, gethcommon.Hash{}, ErrQueuedTxDiscarded))
+	rst := <-tx.Result
+	s.Equal(ErrQueuedTxDiscarded, rst.Error)
+	s.True(rst.WaitTime >= 10*time.Millisecond)
+	s.Equal([]Result{rst}, finished)
+}
+
+func (s *QueueTestSuite) TestDoneHandlerUsesQueue() {
+	counts := make(chan int, 1)
+	s.queue.OnDone(func(*QueuedTx, Result) {
+		counts <- s.queue.Count()
+	})
+
+	tx := Create(context.Background(), SendTxArgs{})
+	s.NoError(s.queue.Enqueue(tx))
+	s.NoError(s.queue.Done(tx.ID, gethcommon.Hash{}, ErrQueuedTxDiscarded))
+	select {
+	case count := <-counts:
+		s.Equal(0, count)
+	case <-time.After(time.Second):
+		s.Fail("done handler deadlocked")
+	}
+}
+
 func (s *QueueTestSuite) TestEviction() {
+	evicted := make(chan *QueuedTx, 1)
+	s.queue.OnEvict(func(tx *QueuedTx) { evicted <- tx })
+
 	var first *QueuedTx
 	for i := 0; i < DefaultTxQueueCap; i++ {
 		tx := Create(context.Background(), SendTxArgs{})
@@ -128,4 +169,23 @@ func (s *QueueTestSuite) TestEviction() {
 	s.NoError(s.queue.Enqueue(tx))
 	s.Equal(DefaultTxQueueCap, s.queue.Count())
 	s.False(s.queue.Has(first.ID))
+	s.Equal(ErrQueuedTxEvicted, (<-first.Result).Error)
+	s.Equal(first, <-evicted)
+}
+
+func (s *QueueTestSuite) TestPriority() {
+	low := Create(context.Background(), SendTxArgs{})
+	high := Create(context.Background(), SendTxArgs{Priority: 1})
+	low2 := Create(context.Background(), SendTxArgs{})
+	for _, tx := range []*QueuedTx{low, high, low2} {
+		s.NoError(s.queue.Enqueue(tx))
+	}
+
+	txs := s.queue.Transactions()
+	s.Equal([]*QueuedTx{high, low, low2}, txs)
+
+	s.Equal(
+		[]string{high.ID, low.ID, low2.ID, "unknown"},
+		s.queue.SortByPriority([]string{low2.ID, "unknown", low.ID, high.ID}),
+	)
 }
@@ -1,17 +1,22 @@
 package transactions
 
 import (
+	"bytes"
 	"context"
 	"math/big"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	ethereum "github.com/ethereum/go-ethereum"
 	gethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
 
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/status-im/status-go/geth/account"
+	"github.com/status-im/status-go/geth/params"
 
 	"github.com/status-im/status-go/geth/rpc"
 )
@@ -24,6 +29,12 @@ const (
 
 	defaultGas     = 90000
 	defaultTimeout = time.Minute
+
+	// defaultWarningsTimeout is how long advisory checks may delay transaction.queued signal.
+	defaultWarningsTimeout = 3 * time.Second
+
+	// drainCheckInterval is how often Drain checks whether transactions are completed.
+	drainCheckInterval = 50 * time.Millisecond
 )
 
 // RPCClientProvider is an interface that provides a way
@@ -32,6 +43,35 @@ type RPCClientProvider interface {
 	RPCClient() *rpc.Client
 }
 
+// SelectedAccountProvider is an interface that provides
+// an account unlocked for the current session.
+type SelectedAccountProvider interface {
+	SelectedAccount() (*account.SelectedExtKey, error)
+}
+
+// AccountKeyProvider is implemented by account providers which can provide keys
+// of accounts other than the selected one.
+type AccountKeyProvider interface {
+	HasSession(address gethcommon.Address) bool
+	HasKey(address gethcommon.Address) bool
+}
+
+// SyncStateProvider reports whether the node is synced enough, i.e. lags
+// behind the network by no more than maxLag blocks, to send transactions.
+type SyncStateProvider interface {
+	IsSynced(maxLag uint64) (bool, error)
+}
+
+// Signer signs transactions of an account which key is kept outside
+// of the node, e.g. on a hardware wallet. Transaction must be signed
+// for the given chain, nil chainID means that replay protection is disabled.
+type Signer interface {
+	SignTx(account gethcommon.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// EnqueueHandler is invoked for every transaction after it is queued.
+type EnqueueHandler func(tx *QueuedTx)
+
 // Manager provides means to manage internal Status Backend (injected into LES)
 type Manager struct {
 	rpcClientProvider RPCClientProvider
@@ -40,25 +80,79 @@ type Manager struct {
 	notify            bool
 	completionTimeout time.Duration
 	rpcCallTimeout    time.Duration
+	warningsTimeout   time.Duration
+	networkMx         sync.RWMutex // mx guards networkID
 	networkID         uint64
+	config            params.TransactionsConfig
 
 	addrLock   *AddrLocker
 	localNonce sync.Map
 	log        log.Logger
+
+	enqueueHandlerMx sync.RWMutex // mx guards enqueueHandler
+	enqueueHandler   EnqueueHandler
+
+	accountProvider SelectedAccountProvider
+	syncState       SyncStateProvider
+
+	fiatPriceMx sync.RWMutex // mx guards fiatPrice
+	fiatPrice   FiatPriceProvider
+
+	broadcast heldBroadcast
+
+	signersMx sync.RWMutex // mx guards signers
+	signers   map[gethcommon.Address]Signer
+
+	draining int32 // set atomically when new transactions are not accepted
+
+	sentValues *valueTracker  // values of recently sent transactions, to apply value limits
+	gasPrices  *gasPriceStore // last gas prices chosen for accounts
+	gasLimit   gasLimitCache  // gas limit of the latest block
+	gasUsage   *gasUsageCache // gas used by recent contract calls, nil if disabled
+	codeCache  codeCache      // whether recently called contracts have code
+	stopped    chan struct{}  // closed when manager is stopped
 }
 
 // NewManager returns a new Manager.
 func NewManager(rpcClientProvider RPCClientProvider) *Manager {
-	return &Manager{
+	m := &Manager{
 		rpcClientProvider: rpcClientProvider,
 		txQueue:           newQueue(),
 		addrLock:          &AddrLocker{},
 		notify:            true,
 		completionTimeout: DefaultTxSendCompletionTimeout,
 		rpcCallTimeout:    defaultTimeout,
+		warningsTimeout:   defaultWarningsTimeout,
 		localNonce:        sync.Map{},
+		signers:           make(map[gethcommon.Address]Signer),
+		sentValues:        newValueTracker(),
+		gasPrices:         newGasPriceStore(),
 		log:               log.New("package", "status-go/geth/transactions.Manager"),
+		config: params.TransactionsConfig{
+			GasMultiplier:      params.DefaultGasMultiplier,
+			MaxInputSize:       params.DefaultMaxTxInputSize,
+			NonceTooLowRetries: params.DefaultNonceTooLowRetries,
+			MaxSyncLag:         params.DefaultMaxSyncLag,
+			MinGasPrices:       map[uint64]uint64{params.StatusChainNetworkID: params.DefaultStatusChainMinGasPrice},
+		},
 	}
+	notifier := newQueueLengthNotifier(QueueLengthDebounce, m.txQueue.Count, func(count int) {
+		if m.notify {
+			NotifyOnQueueLength(count)
+		}
+	})
+	m.txQueue.OnCountChange(notifier.changed)
+	m.txQueue.OnEvict(func(tx *QueuedTx) {
+		if m.notify {
+			NotifyOnEvict(tx)
+		}
+	})
+	m.txQueue.OnDone(func(tx *QueuedTx, result Result) {
+		if m.notify {
+			NotifyOnDone(tx, result)
+		}
+	})
+	return m
 }
 
 // DisableNotificactions turns off notifications on enqueue and return of tx.
@@ -67,20 +161,147 @@ func (m *Manager) DisableNotificactions() {
 	m.notify = false
 }
 
+// Configure applies transactions configuration to the manager.
+// It is not thread safe and must be called only before manager is started.
+func (m *Manager) Configure(config params.TransactionsConfig) {
+	m.config = config
+}
+
+// SetEnqueueHandler sets handler invoked for every queued transaction.
+// While handler is set, transaction.queued signals are not sent.
+// Handler must not block, passing nil removes the handler.
+func (m *Manager) SetEnqueueHandler(handler EnqueueHandler) {
+	m.enqueueHandlerMx.Lock()
+	defer m.enqueueHandlerMx.Unlock()
+	m.enqueueHandler = handler
+}
+
+// SetSelectedAccountProvider sets provider of the account used to auto-complete
+// transactions to trusted recipients.
+// It is not thread safe and must be called only before manager is started.
+func (m *Manager) SetSelectedAccountProvider(provider SelectedAccountProvider) {
+	m.accountProvider = provider
+}
+
+// SetSyncStateProvider sets provider of the sync state of the node. Unless
+// AllowSendDuringSync is set, transactions are refused while node is syncing.
+// It is not thread safe and must be called only before manager is started.
+func (m *Manager) SetSyncStateProvider(provider SyncStateProvider) {
+	m.syncState = provider
+}
+
+// RegisterSigner sets signer used for transactions of the given account
+// instead of the keystore. Passing nil restores keystore signing.
+func (m *Manager) RegisterSigner(address gethcommon.Address, signer Signer) {
+	m.signersMx.Lock()
+	defer m.signersMx.Unlock()
+	if signer == nil {
+		delete(m.signers, address)
+		return
+	}
+	m.signers[address] = signer
+}
+
 // Start starts accepting new transactions into the queue.
 func (m *Manager) Start(networkID uint64) {
 	m.log.Info("start Manager")
+	atomic.StoreInt32(&m.draining, 0)
+	m.networkMx.Lock()
 	m.networkID = networkID
+	m.networkMx.Unlock()
+	m.sentValues.reset()
+	if m.config.DisableReplayProtection && isPublicNetwork(networkID) {
+		m.log.Warn("replay protection is disabled on a public network", "network", networkID)
+	}
 	m.ethTxClient = NewEthTxClient(m.rpcClientProvider.RPCClient())
+	m.gasUsage = newGasUsageCache(m.config.GasUsageCacheSize)
+	m.stopped = make(chan struct{})
 	m.txQueue.Start()
 }
 
+// SwitchNetwork makes transactions to be sent to another network, e.g. after
+// upstream was switched to another chain. Nonces and results cached for the
+// previous network are forgotten.
+func (m *Manager) SwitchNetwork(networkID uint64) {
+	m.log.Info("switch network", "network", networkID)
+	m.networkMx.Lock()
+	m.networkID = networkID
+	m.networkMx.Unlock()
+
+	m.localNonce.Range(func(address, _ interface{}) bool {
+		m.localNonce.Delete(address)
+		return true
+	})
+	m.sentValues.reset()
+	m.codeCache.reset()
+	m.gasLimit.reset()
+	m.ClearGasUsageCache()
+}
+
+// network returns id of the network transactions are sent to.
+func (m *Manager) network() uint64 {
+	m.networkMx.RLock()
+	defer m.networkMx.RUnlock()
+	return m.networkID
+}
+
 // Stop stops accepting new transactions into the queue.
 func (m *Manager) Stop() {
 	m.log.Info("stop Manager")
+	m.discardHeld(ErrShuttingDown)
+	if m.stopped != nil {
+		close(m.stopped)
+		m.stopped = nil
+	}
 	m.txQueue.Stop()
 }
 
+// Drain stops accepting new transactions and waits until transactions which
+// are being completed are finished, or until context is done. Remaining queued
+// transactions, including those held while broadcasting is paused, are discarded
+// with ErrShuttingDown.
+func (m *Manager) Drain(ctx context.Context) error {
+	m.log.Info("drain Manager")
+	atomic.StoreInt32(&m.draining, 1)
+	defer m.discardHeld(ErrShuttingDown)
+
+	ticker := time.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+	var err error
+	for m.hasInprogress() {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		if err != nil {
+			m.log.Warn("transactions are still in progress", "err", err)
+			break
+		}
+	}
+
+	for _, tx := range m.txQueue.Transactions() {
+		// prevents concurrent completion of the transaction, the one which is
+		// still being completed isn't discarded
+		if err := m.txQueue.LockInprogress(tx.ID); err != nil {
+			continue
+		}
+		m.txDone(tx, gethcommon.Hash{}, ErrShuttingDown)
+	}
+	return err
+}
+
+// hasInprogress returns true if any of queued transactions is being completed.
+// Transactions held while broadcasting is paused are not waited for.
+func (m *Manager) hasInprogress() bool {
+	for _, tx := range m.txQueue.Transactions() {
+		if m.txQueue.IsInprogress(tx.ID) && !m.isHeld(tx.ID) {
+			return true
+		}
+	}
+	return false
+}
+
 // TransactionQueue returns a reference to the queue.
 func (m *Manager) TransactionQueue() *TxQueue {
 	return m.txQueue
@@ -88,8 +309,12 @@ func (m *Manager) TransactionQueue() *TxQueue {
 
 // QueueTransaction puts a transaction into the queue.
 func (m *Manager) QueueTransaction(tx *QueuedTx) error {
-	if !tx.Args.Valid() {
-		return ErrInvalidSendTxArgs
+	if err := m.validateTransaction(tx); err != nil {
+		return err
+	}
+	downgraded, err := m.applyEIP1559Fees(tx)
+	if err != nil {
+		return err
 	}
 	to := "<nil>"
 	if tx.Args.To != nil {
@@ -99,12 +324,223 @@ func (m *Manager) QueueTransaction(tx *QueuedTx) error {
 	if err := m.txQueue.Enqueue(tx); err != nil {
 		return err
 	}
+	highValue := m.isHighValue(tx)
+	if highValue {
+		m.log.Info("transaction value is over the limit", "id", tx.ID)
+	} else if highValue = m.isFeeOverFiatCap(tx); highValue {
+		m.log.Info("transaction fee is over the fiat cap", "id", tx.ID)
+	} else if selectedAccount := m.autoCompleteAccount(tx); selectedAccount != nil {
+		m.log.Info("auto-complete transaction to a trusted recipient", "id", tx.ID, "from", tx.Args.From.Hex(), "to", to)
+		go m.CompleteTransaction(tx.ID, selectedAccount) // nolint: errcheck
+		return nil
+	}
+	m.enqueueHandlerMx.RLock()
+	handler := m.enqueueHandler
+	m.enqueueHandlerMx.RUnlock()
+	if handler != nil {
+		// handler decides on the transaction, the app must not act on it too
+		handler(tx)
+		return nil
+	}
 	if m.notify {
-		NotifyOnEnqueue(tx)
+		tx.Warnings = m.queueWarnings(tx)
+		if downgraded {
+			tx.Warnings = append(tx.Warnings, WarningEIP1559Downgraded)
+		}
+		if highValue {
+			NotifyOnHighValueEnqueue(tx)
+		} else {
+			NotifyOnEnqueue(tx)
+		}
+	}
+	return nil
+}
+
+// validateTransaction checks that the transaction can be queued.
+func (m *Manager) validateTransaction(tx *QueuedTx) error {
+	if atomic.LoadInt32(&m.draining) == 1 {
+		return ErrShuttingDown
+	}
+	if !tx.Args.Valid() {
+		return ErrInvalidSendTxArgs
+	}
+	if !m.config.AllowEmptyContractCode {
+		if err := tx.Args.ValidateContractCode(); err != nil {
+			return err
+		}
+	}
+	if err := tx.Args.ValidateInputSize(m.config.MaxInputSize); err != nil {
+		return err
+	}
+	if err := tx.Args.ValidateMeta(); err != nil {
+		return err
+	}
+	if m.config.RejectWithoutAccount && !m.hasSenderKey(tx.Args.From) {
+		return account.ErrNoAccountSelected
+	}
+	return m.ensureSynced()
+}
+
+// LoadGasPrices loads last gas prices chosen for accounts from the file,
+// which is used to persist them further.
+func (m *Manager) LoadGasPrices(path string) error {
+	return m.gasPrices.load(path)
+}
+
+// LastGasPrice returns the last gas price chosen for a transaction of the account.
+func (m *Manager) LastGasPrice(address gethcommon.Address) (*hexutil.Big, bool) {
+	return m.gasPrices.get(address)
+}
+
+// storeGasPrice remembers gas price of a sent transaction if it was chosen by user.
+// Prices derived from EIP-1559 fees are not remembered.
+func (m *Manager) storeGasPrice(tx *QueuedTx) {
+	if tx.Args.GasPrice == nil || tx.derivedPrice {
+		return
+	}
+	if err := m.gasPrices.set(tx.Args.From, tx.Args.GasPrice); err != nil {
+		m.log.Warn("failed to store gas price", "account", tx.Args.From.Hex(), "err", err)
+	}
+}
+
+// isHighValue returns true if transaction value is over the limits of the current network.
+func (m *Manager) isHighValue(tx *QueuedTx) bool {
+	limit, ok := m.config.ValueLimits[m.network()]
+	if !ok {
+		return false
+	}
+	return m.sentValues.exceedsLimit(txValue(tx), limit)
+}
+
+// ensureSynced returns ErrNodeNotSynced if the node is still syncing,
+// unless sending during sync is allowed.
+func (m *Manager) ensureSynced() error {
+	if m.syncState == nil || m.config.AllowSendDuringSync {
+		return nil
+	}
+	synced, err := m.syncState.IsSynced(m.config.MaxSyncLag)
+	if err != nil {
+		return err
+	}
+	if !synced {
+		return ErrNodeNotSynced
 	}
 	return nil
 }
 
+// hasSenderKey returns true if an account is selected or the key of the sender
+// can be obtained otherwise to complete its transactions.
+func (m *Manager) hasSenderKey(from gethcommon.Address) bool {
+	if m.accountProvider == nil {
+		return true
+	}
+	if selectedAccount, err := m.accountProvider.SelectedAccount(); err == nil && selectedAccount != nil {
+		return true
+	}
+	keys, ok := m.accountProvider.(AccountKeyProvider)
+	if !ok {
+		return false
+	}
+	if keys.HasSession(from) {
+		return true
+	}
+	return m.config.UseSenderAccount && keys.HasKey(from)
+}
+
+// txValue returns value of a queued transaction, zero if it is not set.
+func txValue(tx *QueuedTx) *big.Int {
+	if tx.Args.Value == nil {
+		return new(big.Int)
+	}
+	return tx.Args.Value.ToInt()
+}
+
+// autoCompleteAccount returns selected account if transaction is sent by it to
+// one of trusted recipients and auto-complete is enabled, otherwise nil.
+func (m *Manager) autoCompleteAccount(tx *QueuedTx) *account.SelectedExtKey {
+	if !m.config.AutoCompleteEnabled || m.accountProvider == nil || tx.Args.To == nil {
+		return nil
+	}
+	trusted := false
+	for _, recipient := range m.config.AutoCompleteRecipients {
+		if recipient == *tx.Args.To {
+			trusted = true
+			break
+		}
+	}
+	if !trusted {
+		return nil
+	}
+	selectedAccount, err := m.accountProvider.SelectedAccount()
+	if err != nil || selectedAccount.Address != tx.Args.From {
+		return nil
+	}
+	return selectedAccount
+}
+
+// queueWarnings runs advisory checks of a queued transaction. Checks which
+// fail to complete are skipped, as warnings must never block queuing.
+// Checks which query the node run concurrently and are limited by
+// warningsTimeout, so that transaction.queued signal isn't delayed.
+func (m *Manager) queueWarnings(tx *QueuedTx) []string {
+	var warnings []string
+	if tx.Args.To != nil && *tx.Args.To == tx.Args.From {
+		warnings = append(warnings, WarningSelfTransaction)
+	}
+	if threshold, ok := m.config.DustThresholds[m.network()]; ok {
+		value := txValue(tx)
+		if value.Sign() > 0 && value.Cmp(new(big.Int).SetUint64(threshold)) < 0 {
+			warnings = append(warnings, WarningDustValue)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.warningsTimeout)
+	defer cancel()
+	var (
+		wg                                    sync.WaitGroup
+		belowSuggested, isContract, hasNoCode bool
+	)
+	if tx.Args.GasPrice != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			suggested, err := m.ethTxClient.SuggestGasPrice(ctx)
+			if err != nil {
+				m.log.Warn("failed to suggest gas price", "id", tx.ID, "err", err)
+				return
+			}
+			belowSuggested = tx.Args.GasPrice.ToInt().Cmp(suggested) < 0
+		}()
+	}
+	if tx.Args.To != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hasCode, err := m.hasCode(ctx, *tx.Args.To)
+			if err != nil {
+				m.log.Warn("failed to get recipient code", "id", tx.ID, "err", err)
+				return
+			}
+			// plain transfer to a contract or contract call of an account without code
+			call := len(tx.Args.GetInput()) > 0
+			isContract = hasCode && !call
+			hasNoCode = !hasCode && call
+		}()
+	}
+	wg.Wait()
+
+	if belowSuggested {
+		warnings = append(warnings, WarningGasPriceBelowSuggested)
+	}
+	if isContract {
+		warnings = append(warnings, WarningRecipientIsContract)
+	}
+	if hasNoCode {
+		warnings = append(warnings, WarningRecipientHasNoCode)
+	}
+	return warnings
+}
+
 func (m *Manager) txDone(tx *QueuedTx, hash gethcommon.Hash, err error) {
 	if err := m.txQueue.Done(tx.ID, hash, err); err == ErrQueuedTxIDNotFound {
 		m.log.Warn("transaction is already removed from a queue", "ID", tx.ID)
@@ -128,6 +564,10 @@ func (m *Manager) WaitForTransaction(tx *QueuedTx) Result {
 		case rst := <-tx.Result:
 			return rst
 		case <-time.After(m.completionTimeout):
+			// held transaction is signed already, it is finished on resume
+			if m.isHeld(tx.ID) {
+				continue
+			}
 			m.txDone(tx, gethcommon.Hash{}, ErrQueuedTxTimedOut)
 		}
 	}
@@ -150,25 +590,52 @@ func (m *Manager) NotifyErrored(id string, inputError error) error {
 
 // CompleteTransaction instructs backend to complete sending of a given transaction.
 func (m *Manager) CompleteTransaction(id string, account *account.SelectedExtKey) (hash gethcommon.Hash, err error) {
+	result := m.CompleteTransactionResult(id, account)
+	return result.Hash, result.Error
+}
+
+// CompleteTransactionResult completes transaction like CompleteTransaction does,
+// but also reports the stage at which completion failed. While broadcasting is
+// paused, ErrBroadcastHeld is returned with the hash of the signed transaction,
+// which stays in the queue until broadcasting is resumed.
+func (m *Manager) CompleteTransactionResult(id string, account *account.SelectedExtKey) Result {
 	m.log.Info("complete transaction", "id", id)
 	tx, err := m.txQueue.Get(id)
 	if err != nil {
 		m.log.Warn("error getting a queued transaction", "err", err)
-		return hash, err
+		return Result{Error: err}
 	}
 	if err := m.txQueue.LockInprogress(id); err != nil {
 		m.log.Warn("can't process transaction", "err", err)
-		return hash, err
+		return Result{Error: err}
 	}
 
 	if err := m.validateAccount(tx, account); err != nil {
-		m.txDone(tx, hash, err)
-		return hash, err
+		m.txDone(tx, gethcommon.Hash{}, err)
+		return Result{Error: err, Stage: StageUnlock}
+	}
+	hash, stage, err := m.completeTransaction(account, tx)
+	if err == ErrBroadcastHeld {
+		return Result{Hash: hash, Error: err, Stage: stage}
 	}
-	hash, err = m.completeTransaction(account, tx)
 	m.log.Info("finally completed transaction", "id", tx.ID, "hash", hash, "err", err)
+	if err == nil {
+		m.txSent(tx, hash)
+	}
 	m.txDone(tx, hash, err)
-	return hash, err
+	if err != nil {
+		return Result{Hash: hash, Error: err, Stage: stage}
+	}
+	return Result{Hash: hash}
+}
+
+// txSent records the transaction sent to the network.
+func (m *Manager) txSent(tx *QueuedTx, hash gethcommon.Hash) {
+	m.sentValues.add(txValue(tx))
+	m.storeGasPrice(tx)
+	if m.gasUsage != nil {
+		go m.learnGasUsage(tx, hash, m.stopped)
+	}
 }
 
 // make sure that only account which created the tx can complete it
@@ -186,96 +653,254 @@ func (m *Manager) validateAccount(tx *QueuedTx, selectedAccount *account.Selecte
 	return nil
 }
 
-func (m *Manager) completeTransaction(selectedAccount *account.SelectedExtKey, queuedTx *QueuedTx) (hash gethcommon.Hash, err error) {
+func (m *Manager) completeTransaction(selectedAccount *account.SelectedExtKey, queuedTx *QueuedTx) (hash gethcommon.Hash, stage Stage, err error) {
 	m.log.Info("complete transaction", "id", queuedTx.ID)
+	stage = StageEstimate
 	m.addrLock.LockAddr(queuedTx.Args.From)
-	var localNonce uint64
-	if val, ok := m.localNonce.Load(queuedTx.Args.From); ok {
-		localNonce = val.(uint64)
-	}
 	var nonce uint64
 	defer func() {
 		// nonce should be incremented only if tx completed without error
 		// if upstream node returned nonce higher than ours we will stick to it
 		if err == nil {
 			m.localNonce.Store(queuedTx.Args.From, nonce+1)
+			if m.config.DiscardSuperseded {
+				m.discardSuperseded(queuedTx, nonce)
+			}
 		}
 		m.addrLock.UnlockAddr(queuedTx.Args.From)
 
 	}()
+	if nonce, err = m.nextNonce(queuedTx.Args.From); err != nil {
+		return hash, stage, err
+	}
+	args := queuedTx.Args
+	if !args.Valid() {
+		return hash, stage, ErrInvalidSendTxArgs
+	}
+	gasPrice, err := m.gasPrice(args)
+	if err != nil {
+		return hash, stage, err
+	}
+	gas, err := m.gas(queuedTx, gasPrice)
+	if err != nil {
+		return hash, stage, err
+	}
+
+	value := (*big.Int)(args.Value)
+	toAddr := gethcommon.Address{}
+	if args.To != nil {
+		toAddr = *args.To
+	}
+	m.log.Info(
+		"preparing raw transaction",
+		"from", args.From.Hex(),
+		"to", toAddr.Hex(),
+		"gas", gas,
+		"gasPrice", gasPrice,
+		"value", value,
+	)
+	tx := types.NewTransaction(nonce, toAddr, value, gas, gasPrice, args.GetInput())
+	stage = StageSign
+	signedTx, err := m.signTx(queuedTx, selectedAccount, tx)
+	if err != nil {
+		return hash, stage, err
+	}
+	stage = StageBroadcast
+	if m.holdBroadcast(heldTx{queuedTx: queuedTx, tx: signedTx}) {
+		return signedTx.Hash(), stage, ErrBroadcastHeld
+	}
+	if signedTx, err = m.sendRetryingNonce(selectedAccount, queuedTx, signedTx); err != nil {
+		return hash, stage, err
+	}
+	nonce = signedTx.Nonce()
+	return signedTx.Hash(), "", nil
+}
+
+// nextNonce returns the nonce of the next transaction of the account: the pending
+// nonce, unless local nonce or nonces reserved by held transactions are ahead of it.
+// A nonce gap is reported if the local nonce is ahead of the pending one.
+func (m *Manager) nextNonce(address gethcommon.Address) (uint64, error) {
+	var localNonce uint64
+	if val, ok := m.localNonce.Load(address); ok {
+		localNonce = val.(uint64)
+	}
 	ctx, cancel := context.WithTimeout(context.Background(), m.rpcCallTimeout)
 	defer cancel()
-	nonce, err = m.ethTxClient.PendingNonceAt(ctx, queuedTx.Args.From)
+	nonce, err := m.ethTxClient.PendingNonceAt(ctx, address)
 	if err != nil {
-		return hash, err
+		return 0, err
+	}
+	if err := m.checkNonceGap(address, NonceStatus{Pending: nonce, Local: localNonce}); err != nil {
+		return 0, err
 	}
-	// if upstream node returned nonce higher than ours we will use it, as it probably means
-	// that another client was used for sending transactions
+	// if upstream returned nonce higher than ours, another client was used for sending
 	if localNonce > nonce {
 		nonce = localNonce
 	}
-	args := queuedTx.Args
-	if !args.Valid() {
-		return hash, ErrInvalidSendTxArgs
+	// nonces of transactions held while broadcasting is paused are reserved
+	if next, ok := m.nextHeldNonce(address); ok && next > nonce {
+		nonce = next
 	}
+	return nonce, nil
+}
+
+// gasPrice returns gas price of the transaction, the suggested one if it isn't set,
+// raised to the minimum of the network.
+func (m *Manager) gasPrice(args SendTxArgs) (*big.Int, error) {
 	gasPrice := (*big.Int)(args.GasPrice)
 	if args.GasPrice == nil {
-		ctx, cancel = context.WithTimeout(context.Background(), m.rpcCallTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), m.rpcCallTimeout)
 		defer cancel()
+		var err error
 		gasPrice, err = m.ethTxClient.SuggestGasPrice(ctx)
 		if err != nil {
-			return hash, err
+			if m.config.FallbackGasPrice == 0 {
+				return nil, err
+			}
+			m.log.Warn("failed to suggest gas price, fallback is used", "err", err, "gasPrice", m.config.FallbackGasPrice)
+			gasPrice = new(big.Int).SetUint64(m.config.FallbackGasPrice)
 		}
 	}
+	if minGasPrice, ok := m.config.MinGasPrices[m.network()]; ok {
+		if floor := new(big.Int).SetUint64(minGasPrice); gasPrice.Cmp(floor) < 0 {
+			m.log.Info("gas price is raised to the network minimum", "gasPrice", gasPrice, "minGasPrice", floor)
+			gasPrice = floor
+		}
+	}
+	return gasPrice, nil
+}
 
-	chainID := big.NewInt(int64(m.networkID))
-	value := (*big.Int)(args.Value)
-	toAddr := gethcommon.Address{}
-	if args.To != nil {
-		toAddr = *args.To
+// gas returns gas of the transaction, estimated if it isn't set.
+func (m *Manager) gas(queuedTx *QueuedTx, gasPrice *big.Int) (uint64, error) {
+	args := queuedTx.Args
+	if args.Gas != nil {
+		gas := uint64(*args.Gas)
+		return gas, m.checkGasLimit(gas)
 	}
+	ctx, cancel := context.WithTimeout(context.Background(), m.rpcCallTimeout)
+	defer cancel()
+	gas, err := m.ethTxClient.EstimateGas(ctx, ethereum.CallMsg{
+		From:     args.From,
+		To:       args.To,
+		GasPrice: gasPrice,
+		Value:    (*big.Int)(args.Value),
+		Data:     args.GetInput(),
+	})
+	if err != nil {
+		return 0, err
+	}
+	gas = m.reconcileGasEstimate(queuedTx, gas)
+	gas, err = m.applyGasMultiplier(queuedTx, gas)
+	if err != nil {
+		return 0, err
+	}
+	if gas < defaultGas {
+		m.log.Info("default gas will be used. estimated gas", gas, "is lower than", defaultGas)
+		gas = defaultGas
+	}
+	return gas, nil
+}
 
-	var gas uint64
-	if args.Gas == nil {
-		ctx, cancel = context.WithTimeout(context.Background(), m.rpcCallTimeout)
-		defer cancel()
-		gas, err = m.ethTxClient.EstimateGas(ctx, ethereum.CallMsg{
-			From:     args.From,
-			To:       args.To,
-			GasPrice: gasPrice,
-			Value:    value,
-			Data:     args.GetInput(),
-		})
-		if err != nil {
-			return hash, err
-		}
-		if gas < defaultGas {
-			m.log.Info("default gas will be used. estimated gas", gas, "is lower than", defaultGas)
-			gas = defaultGas
+// sendRetryingNonce sends the signed transaction. Nonce might be advanced by another
+// client after it was queried, so if it is too low, the nonce is refreshed and the
+// transaction is signed again, at most NonceTooLowRetries times. Sent transaction
+// is returned.
+func (m *Manager) sendRetryingNonce(selectedAccount *account.SelectedExtKey, queuedTx *QueuedTx, signedTx *types.Transaction) (*types.Transaction, error) {
+	err := m.sendTransaction(signedTx)
+	for retry := 0; isNonceTooLow(err) && retry < m.config.NonceTooLowRetries; retry++ {
+		m.log.Warn("nonce too low, retrying with refreshed nonce", "id", queuedTx.ID, "nonce", signedTx.Nonce())
+		if signedTx, err = m.resignWithPendingNonce(selectedAccount, queuedTx, signedTx); err != nil {
+			return nil, err
 		}
-	} else {
-		gas = uint64(*args.Gas)
+		err = m.sendTransaction(signedTx)
 	}
+	if isNonceTooLow(err) {
+		return nil, ErrNonceTooLow
+	}
+	return signedTx, err
+}
 
-	m.log.Info(
-		"preparing raw transaction",
-		"from", args.From.Hex(),
-		"to", toAddr.Hex(),
-		"gas", gas,
-		"gasPrice", gasPrice,
-		"value", value,
-	)
-	tx := types.NewTransaction(nonce, toAddr, value, gas, gasPrice, args.GetInput())
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), selectedAccount.AccountKey.PrivateKey)
+// resignWithPendingNonce signs the transaction again with the pending nonce of the account.
+func (m *Manager) resignWithPendingNonce(selectedAccount *account.SelectedExtKey, queuedTx *QueuedTx, signedTx *types.Transaction) (*types.Transaction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), m.rpcCallTimeout)
+	defer cancel()
+	nonce, err := m.ethTxClient.PendingNonceAt(ctx, queuedTx.Args.From)
 	if err != nil {
-		return hash, err
+		return nil, err
 	}
-	ctx, cancel = context.WithTimeout(context.Background(), m.rpcCallTimeout)
+	tx := types.NewTransaction(nonce, *signedTx.To(), signedTx.Value(), signedTx.Gas(), signedTx.GasPrice(), signedTx.Data())
+	return m.signTx(queuedTx, selectedAccount, tx)
+}
+
+// sendTransaction sends the signed transaction to the network.
+func (m *Manager) sendTransaction(signedTx *types.Transaction) error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.rpcCallTimeout)
 	defer cancel()
-	if err := m.ethTxClient.SendTransaction(ctx, signedTx); err != nil {
-		return hash, err
+	return m.ethTxClient.SendTransaction(ctx, signedTx)
+}
+
+// signer returns a signer used to sign transactions. EIP-155 signer is used
+// unless replay protection is disabled for legacy chains.
+func (m *Manager) signer() types.Signer {
+	if m.config.DisableReplayProtection {
+		return types.HomesteadSigner{}
 	}
-	return signedTx.Hash(), nil
+	return types.NewEIP155Signer(big.NewInt(int64(m.network())))
+}
+
+// signTx signs transaction with the signer registered for the account,
+// or with the account's key from the keystore by default.
+func (m *Manager) signTx(queuedTx *QueuedTx, selectedAccount *account.SelectedExtKey, tx *types.Transaction) (*types.Transaction, error) {
+	m.signersMx.RLock()
+	signer, ok := m.signers[selectedAccount.Address]
+	m.signersMx.RUnlock()
+	if !ok {
+		return types.SignTx(tx, m.signer(), selectedAccount.AccountKey.PrivateKey)
+	}
+
+	var chainID *big.Int
+	if !m.config.DisableReplayProtection {
+		chainID = big.NewInt(int64(m.network()))
+	}
+	m.log.Info("sign transaction with external signer", "id", queuedTx.ID, "account", selectedAccount.Address.Hex())
+	if m.notify {
+		NotifyOnExternalSign(queuedTx)
+	}
+	return signer.SignTx(selectedAccount.Address, tx, chainID)
+}
+
+// applyGasMultiplier multiplies estimated gas by the configured multiplier,
+// or by the one passed with the transaction context, and caps the result
+// at the latest block gas limit.
+func (m *Manager) applyGasMultiplier(queuedTx *QueuedTx, gas uint64) (uint64, error) {
+	multiplier := m.config.GasMultiplier
+	if m.isLargeInput(queuedTx) && m.config.LargeInputGasMultiplier > multiplier {
+		multiplier = m.config.LargeInputGasMultiplier
+	}
+	if val, ok := gasMultiplierFromContext(queuedTx.Context); ok {
+		multiplier = val
+	}
+	// multipliers below 1 would make transaction run out of gas
+	if multiplier <= 1 {
+		return gas, nil
+	}
+
+	gasLimit, err := m.blockGasLimit()
+	if err != nil {
+		return gas, err
+	}
+
+	adjusted := uint64(float64(gas) * multiplier)
+	if adjusted > gasLimit {
+		m.log.Info("adjusted gas is capped by block gas limit", "gas", adjusted, "limit", gasLimit)
+		adjusted = gasLimit
+	}
+	return adjusted, nil
+}
+
+// isLargeInput returns true if transaction input is large enough to apply LargeInputGasMultiplier.
+func (m *Manager) isLargeInput(queuedTx *QueuedTx) bool {
+	return m.config.LargeInputSize > 0 && len(queuedTx.Args.GetInput()) >= m.config.LargeInputSize
 }
 
 // DiscardTransaction discards a given transaction from transaction queue
@@ -291,45 +916,399 @@ func (m *Manager) DiscardTransaction(id string) error {
 	return err
 }
 
+// FailTransaction removes the transaction from the queue with the given error,
+// even if the error is transient, and sends transaction.failed signal. It is used
+// when completion of the transaction isn't going to be retried.
+func (m *Manager) FailTransaction(id string, reason error) error {
+	tx, err := m.txQueue.Get(id)
+	if err != nil {
+		return err
+	}
+	// prevents concurrent completion of the transaction
+	if err := m.txQueue.LockInprogress(id); err != nil {
+		return err
+	}
+	m.log.Info("fail transaction", "id", id, "reason", reason)
+	if err := m.txQueue.Fail(id, reason); err != nil {
+		return err
+	}
+	if m.notify {
+		NotifyOnReturn(tx, reason)
+	}
+	return nil
+}
+
+// DiscardAllTransactions discards all queued transactions and returns results per
+// transaction id. Transactions which are being completed are not discarded and
+// ErrQueuedTxInProgress is returned for them, so every transaction is resolved once.
+func (m *Manager) DiscardAllTransactions() map[string]error {
+	results := make(map[string]error)
+	for _, tx := range m.txQueue.Transactions() {
+		// prevents concurrent completion of the transaction
+		if err := m.txQueue.LockInprogress(tx.ID); err != nil {
+			results[tx.ID] = err
+			continue
+		}
+		m.txDone(tx, gethcommon.Hash{}, ErrQueuedTxDiscarded)
+		results[tx.ID] = nil
+	}
+	return results
+}
+
+// DiscardTransactionsFrom discards all queued transactions sent from a given address,
+// except for those which are being completed. Transactions are returned with a given reason.
+func (m *Manager) DiscardTransactionsFrom(address gethcommon.Address, reason error) {
+	for _, tx := range m.txQueue.Transactions() {
+		if tx.Args.From != address {
+			continue
+		}
+		// prevents concurrent completion of the transaction, the one which is
+		// being completed already isn't discarded
+		if err := m.txQueue.LockInprogress(tx.ID); err != nil {
+			continue
+		}
+		m.log.Info("discard transaction", "id", tx.ID, "reason", reason)
+		m.txDone(tx, gethcommon.Hash{}, reason)
+	}
+}
+
+// ExportPendingRequests returns queued transactions which are not being completed
+// at the moment, ordered by priority, so that they can be signed by an external signer.
+func (m *Manager) ExportPendingRequests() []SerializedSignRequest {
+	var requests []SerializedSignRequest
+	for _, tx := range m.txQueue.Transactions() {
+		if m.txQueue.IsInprogress(tx.ID) {
+			continue
+		}
+		requests = append(requests, NewSerializedSignRequest(tx))
+	}
+	return requests
+}
+
+// ImportSignedResult sends a transaction signed by an external signer for
+// a queued transaction with a given id. The signed transaction must be
+// signed by the same account which created the queued transaction and match
+// its recipient, value and input, as well as gas and nonce if they are set.
+// It is sent like a completed transaction, so it is held while broadcasting
+// is paused. SkipIfMinedKey is respected like in SendRawTransaction.
+func (m *Manager) ImportSignedResult(ctx context.Context, id string, signedRaw hexutil.Bytes) (hash gethcommon.Hash, err error) {
+	m.log.Info("import signed transaction", "id", id)
+	tx, err := m.txQueue.Get(id)
+	if err != nil {
+		m.log.Warn("error getting a queued transaction", "err", err)
+		return hash, err
+	}
+	signedTx, sender, err := m.decodeSignedTx(signedRaw)
+	if err != nil {
+		return hash, err
+	}
+	if sender != tx.Args.From {
+		m.log.Warn("signed transaction does not belong to the queued transaction sender", "err", ErrInvalidCompleteTxSender)
+		return hash, ErrInvalidCompleteTxSender
+	}
+	if !matchesArgs(signedTx, tx.Args) {
+		m.log.Warn("signed transaction does not match the queued transaction", "id", id, "err", ErrSignedTxMismatch)
+		return hash, ErrSignedTxMismatch
+	}
+	if err := m.txQueue.LockInprogress(id); err != nil {
+		m.log.Warn("can't process transaction", "err", err)
+		return hash, err
+	}
+
+	hash, err = m.sendImported(ctx, tx, signedTx)
+	if err == ErrBroadcastHeld {
+		return hash, err
+	}
+	m.log.Info("finally sent imported transaction", "id", tx.ID, "hash", hash, "err", err)
+	if err == nil {
+		m.txSent(tx, hash)
+	}
+	m.txDone(tx, hash, err)
+	return hash, err
+}
+
+// sendImported sends the transaction signed for the queued one, or holds it
+// while broadcasting is paused.
+func (m *Manager) sendImported(ctx context.Context, tx *QueuedTx, signedTx *types.Transaction) (hash gethcommon.Hash, err error) {
+	m.addrLock.LockAddr(tx.Args.From)
+	defer m.addrLock.UnlockAddr(tx.Args.From)
+	if m.holdBroadcast(heldTx{queuedTx: tx, tx: signedTx, skipIfMined: skipIfMinedFromContext(ctx)}) {
+		return signedTx.Hash(), ErrBroadcastHeld
+	}
+	ctx, cancel := context.WithTimeout(ctx, m.rpcCallTimeout)
+	defer cancel()
+	if err := m.sendSigned(ctx, tx.Args.From, signedTx); err != nil {
+		return hash, err
+	}
+	if m.config.DiscardSuperseded {
+		m.discardSuperseded(tx, signedTx.Nonce())
+	}
+	return signedTx.Hash(), nil
+}
+
+// matchesArgs returns true if the signed transaction has recipient, value
+// and input of the arguments, as well as their gas and nonce if they are set.
+func matchesArgs(signedTx *types.Transaction, args SendTxArgs) bool {
+	if (args.To == nil) != (signedTx.To() == nil) || (args.To != nil && *args.To != *signedTx.To()) {
+		return false
+	}
+	value := new(big.Int)
+	if args.Value != nil {
+		value = args.Value.ToInt()
+	}
+	if value.Cmp(signedTx.Value()) != 0 || !bytes.Equal(args.GetInput(), signedTx.Data()) {
+		return false
+	}
+	if args.Gas != nil && uint64(*args.Gas) != signedTx.Gas() {
+		return false
+	}
+	return args.Nonce == nil || uint64(*args.Nonce) == signedTx.Nonce()
+}
+
+// NonceStatus returns confirmed, pending and local nonces of the given account.
+func (m *Manager) NonceStatus(ctx context.Context, address gethcommon.Address) (status NonceStatus, err error) {
+	if status.Confirmed, err = m.ethTxClient.NonceAt(ctx, address, nil); err != nil {
+		return status, err
+	}
+	if status.Pending, err = m.ethTxClient.PendingNonceAt(ctx, address); err != nil {
+		return status, err
+	}
+	if val, ok := m.localNonce.Load(address); ok {
+		status.Local = val.(uint64)
+	}
+	return status, nil
+}
+
+// checkNonceGap reports a gap between the local and pending nonces of the account.
+// ErrNonceGap is returned if transactions are refused on a gap.
+func (m *Manager) checkNonceGap(address gethcommon.Address, status NonceStatus) error {
+	if !status.HasGap() {
+		return nil
+	}
+	m.log.Warn("nonce gap detected", "address", address.Hex(), "pending", status.Pending, "local", status.Local)
+	if m.notify {
+		NotifyOnNonceGap(address, status)
+	}
+	if m.config.RefuseOnNonceGap {
+		return ErrNonceGap
+	}
+	return nil
+}
+
+// RefreshNonce resets the local nonce of the account to its pending nonce known to the network.
+// It allows to recover from nonce desync, e.g. after transactions sent by this node were dropped.
+func (m *Manager) RefreshNonce(ctx context.Context, address gethcommon.Address) error {
+	m.addrLock.LockAddr(address)
+	defer m.addrLock.UnlockAddr(address)
+	ctx, cancel := context.WithTimeout(ctx, m.rpcCallTimeout)
+	defer cancel()
+	nonce, err := m.ethTxClient.PendingNonceAt(ctx, address)
+	if err != nil {
+		return err
+	}
+	m.log.Info("refresh local nonce", "address", address.Hex(), "nonce", nonce)
+	m.localNonce.Store(address, nonce)
+	return nil
+}
+
+// SendRawTransaction broadcasts a transaction signed outside of the node.
+// It doesn't go through the queue, so no confirmation is needed.
+// If SkipIfMinedKey is set in the context and the nonce of the transaction is
+// already confirmed, the transaction isn't broadcast again. Its hash is returned
+// if it was mined, otherwise ErrNonceAlreadyMined.
+// Like for completed transactions, transaction.failed signal is sent if sending
+// fails and transaction.done signal is sent with the outcome. Signals have
+// an id generated for the transaction, as it isn't queued.
+func (m *Manager) SendRawTransaction(ctx context.Context, signedRaw hexutil.Bytes) (hash gethcommon.Hash, err error) {
+	signedTx, sender, err := m.decodeSignedTx(signedRaw)
+	if err != nil {
+		return hash, err
+	}
+	tx := Create(ctx, argsFromSignedTx(sender, signedTx))
+	m.log.Info("send raw transaction", "id", tx.ID, "from", sender.Hex(), "hash", signedTx.Hash().Hex())
+
+	if err = m.sendRaw(ctx, sender, signedTx); err == nil {
+		hash = signedTx.Hash()
+	}
+	if m.notify {
+		NotifyOnReturn(tx, err)
+		NotifyOnDone(tx, Result{Hash: hash, Error: err})
+	}
+	return hash, err
+}
+
+// sendRaw broadcasts the transaction signed outside of the node.
+func (m *Manager) sendRaw(ctx context.Context, sender gethcommon.Address, signedTx *types.Transaction) error {
+	m.addrLock.LockAddr(sender)
+	defer m.addrLock.UnlockAddr(sender)
+	ctx, cancel := context.WithTimeout(ctx, m.rpcCallTimeout)
+	defer cancel()
+	return m.sendSigned(ctx, sender, signedTx)
+}
+
+// argsFromSignedTx returns args the signed transaction could be sent with.
+func argsFromSignedTx(sender gethcommon.Address, signedTx *types.Transaction) SendTxArgs {
+	gas := hexutil.Uint64(signedTx.Gas())
+	nonce := hexutil.Uint64(signedTx.Nonce())
+	return SendTxArgs{
+		From:     sender,
+		To:       signedTx.To(),
+		Gas:      &gas,
+		GasPrice: (*hexutil.Big)(signedTx.GasPrice()),
+		Value:    (*hexutil.Big)(signedTx.Value()),
+		Nonce:    &nonce,
+		Input:    signedTx.Data(),
+	}
+}
+
+// sendSigned broadcasts the transaction signed by the sender, unless SkipIfMinedKey
+// is set in the context and the transaction is already mined. Local nonce of the
+// sender is kept in line with sent transactions. The sender's address must be locked.
+func (m *Manager) sendSigned(ctx context.Context, sender gethcommon.Address, signedTx *types.Transaction) error {
+	if skipIfMinedFromContext(ctx) {
+		mined, err := m.isNonceMined(ctx, sender, signedTx)
+		if err != nil || mined {
+			return err
+		}
+	}
+	if err := m.ethTxClient.SendTransaction(ctx, signedTx); err != nil {
+		return err
+	}
+	if val, ok := m.localNonce.Load(sender); !ok || val.(uint64) <= signedTx.Nonce() {
+		m.localNonce.Store(sender, signedTx.Nonce()+1)
+	}
+	return nil
+}
+
+// isNonceMined checks if the nonce of the transaction is already confirmed.
+// An error is returned if it's used by another transaction.
+func (m *Manager) isNonceMined(ctx context.Context, sender gethcommon.Address, signedTx *types.Transaction) (bool, error) {
+	confirmed, err := m.ethTxClient.NonceAt(ctx, sender, nil)
+	if err != nil || confirmed <= signedTx.Nonce() {
+		return false, err
+	}
+	mined, err := m.ethTxClient.TransactionMined(ctx, signedTx.Hash())
+	if err != nil {
+		return false, err
+	}
+	if !mined {
+		return false, ErrNonceAlreadyMined
+	}
+	m.log.Info("raw transaction is already mined", "from", sender.Hex(), "hash", signedTx.Hash().Hex())
+	return true, nil
+}
+
+// decodeSignedTx decodes RLP encoded signed transaction and recovers its sender.
+// Unless replay protection is disabled, transaction must be signed for the current network.
+func (m *Manager) decodeSignedTx(signedRaw hexutil.Bytes) (*types.Transaction, gethcommon.Address, error) {
+	var sender gethcommon.Address
+	signedTx := new(types.Transaction)
+	if err := rlp.DecodeBytes(signedRaw, signedTx); err != nil {
+		return nil, sender, err
+	}
+	if !m.config.DisableReplayProtection && (!signedTx.Protected() || signedTx.ChainId().Uint64() != m.network()) {
+		return nil, sender, ErrInvalidChainID
+	}
+	sender, err := types.Sender(m.signer(), signedTx)
+	if err != nil {
+		return nil, sender, err
+	}
+	return signedTx, sender, nil
+}
+
+// discardSuperseded discards queued transactions of the same account which
+// have explicit nonce equal to the nonce of the sent transaction.
+func (m *Manager) discardSuperseded(sent *QueuedTx, nonce uint64) {
+	for _, tx := range m.txQueue.Transactions() {
+		if tx.ID == sent.ID || tx.Args.From != sent.Args.From || tx.Args.Nonce == nil {
+			continue
+		}
+		if uint64(*tx.Args.Nonce) != nonce {
+			continue
+		}
+		// prevents concurrent completion of the transaction, the one which is
+		// being completed already isn't discarded
+		if err := m.txQueue.LockInprogress(tx.ID); err != nil {
+			continue
+		}
+		m.log.Info("discard superseded transaction", "id", tx.ID, "nonce", nonce, "sent", sent.ID)
+		m.txDone(tx, gethcommon.Hash{}, ErrQueuedTxSuperseded)
+	}
+}
+
 // SendTransactionRPCHandler is a handler for eth_sendTransaction method.
 // It accepts one param which is a slice with a map of transaction params.
 func (m *Manager) SendTransactionRPCHandler(ctx context.Context, args ...interface{}) (interface{}, error) {
 	m.log.Info("SendTransactionRPCHandler called")
-	tx := Create(ctx, m.rpcCalltoSendTxArgs(args...))
+	sendArgs, err := m.rpcCalltoSendTxArgs(args...)
+	if err != nil {
+		return nil, err
+	}
+	tx := Create(ctx, sendArgs)
 	if err := m.QueueTransaction(tx); err != nil {
 		return nil, err
 	}
-	rst := m.WaitForTransaction(tx)
+	rst := m.waitForTransactionContext(ctx, tx)
 	if rst.Error != nil {
 		return nil, rst.Error
 	}
 	return rst.Hash.Hex(), nil
 }
 
-func (m *Manager) rpcCalltoSendTxArgs(args ...interface{}) SendTxArgs {
-	var err error
-	var fromAddr, toAddr gethcommon.Address
+// waitForTransactionContext waits for the transaction like WaitForTransaction.
+// If ctx is canceled before the transaction is completed, the transaction is
+// discarded, so that it's never sent after the caller gave up. Transaction which
+// is being completed already can't be discarded and its result is returned.
+func (m *Manager) waitForTransactionContext(ctx context.Context, tx *QueuedTx) Result {
+	result := make(chan Result, 1)
+	go func() {
+		result <- m.WaitForTransaction(tx)
+	}()
+
+	select {
+	case rst := <-result:
+		return rst
+	case <-ctx.Done():
+	}
+	// prevents concurrent completion of the transaction
+	if err := m.txQueue.LockInprogress(tx.ID); err == nil {
+		m.log.Info("discard transaction of canceled call", "id", tx.ID)
+		m.txDone(tx, gethcommon.Hash{}, ErrQueuedTxDiscarded)
+	}
+	return <-result
+}
 
+func (m *Manager) rpcCalltoSendTxArgs(args ...interface{}) (SendTxArgs, error) {
 	rpcCall := rpc.Call{Params: args}
-	fromAddr, err = rpcCall.ParseFromAddress()
+	fromAddr, err := rpcCall.ParseFromAddress()
 	if err != nil {
-		fromAddr = gethcommon.HexToAddress("0x0")
+		return SendTxArgs{}, err
 	}
 
-	toAddr, err = rpcCall.ParseToAddress()
-	if err != nil {
-		toAddr = gethcommon.HexToAddress("0x0")
+	// missing recipient means contract creation, but invalid one is
+	// refused rather than replaced with the zero address
+	var toAddr *gethcommon.Address
+	if addr, err := rpcCall.ParseToAddress(); err == nil {
+		toAddr = &addr
+	} else if err != rpc.ErrMissingToAddress {
+		return SendTxArgs{}, err
+	}
+
+	// missing value means zero value, e.g. for contract calls
+	value := rpcCall.ParseValue()
+	if value == nil {
+		value = (*hexutil.Big)(big.NewInt(0))
 	}
 
 	input := rpcCall.ParseInput()
 	data := rpcCall.ParseData()
 	return SendTxArgs{
-		To:       &toAddr,
+		To:       toAddr,
 		From:     fromAddr,
-		Value:    rpcCall.ParseValue(),
+		Value:    value,
 		Input:    input,
 		Data:     data,
 		Gas:      rpcCall.ParseGas(),
 		GasPrice: rpcCall.ParseGasPrice(),
-	}
+	}, nil
 }
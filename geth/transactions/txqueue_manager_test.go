@@ -2,6 +2,7 @@ package transactions
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"errors"
 	"math/big"
 	"sync"
@@ -21,10 +22,15 @@ import (
 	"github.com/status-im/status-go/geth/account"
 	"github.com/status-im/status-go/geth/params"
 	"github.com/status-im/status-go/geth/rpc"
+	"github.com/status-im/status-go/geth/signal"
 	"github.com/status-im/status-go/geth/transactions/fake"
 	. "github.com/status-im/status-go/t/utils"
 )
 
+func TestTxQueueTestSuite(t *testing.T) {
+	suite.Run(t, new(TxQueueTestSuite))
+}
+
 type TxQueueTestSuite struct {
 	suite.Suite
 	rpcClientMockCtrl *gomock.Controller
@@ -56,6 +62,8 @@ func (s *TxQueueTestSuite) SetupTest() {
 	s.manager.DisableNotificactions()
 	s.manager.completionTimeout = time.Second
 	s.manager.rpcCallTimeout = time.Second
+	// explicit gas is checked against the configured limit, instead of the latest block
+	s.manager.config.MaxGasLimits = map[uint64]uint64{params.RopstenNetworkID: testBlockGasLimit}
 	s.manager.Start(params.RopstenNetworkID)
 }
 
@@ -73,6 +81,18 @@ var (
 	testNonce    = hexutil.Uint64(10)
 )
 
+// testBlockGasLimit is the limit of explicit gas configured for the test network
+const testBlockGasLimit uint64 = 8000000
+
+// newSelectedAccount returns Account1 with a freshly generated signing key.
+func newSelectedAccount() *account.SelectedExtKey {
+	key, _ := crypto.GenerateKey()
+	return &account.SelectedExtKey{
+		Address:    account.FromAddress(TestConfig.Account1.Address),
+		AccountKey: &keystore.Key{PrivateKey: key},
+	}
+}
+
 func (s *TxQueueTestSuite) setupTransactionPoolAPI(tx *QueuedTx, returnNonce, resultNonce hexutil.Uint64, account *account.SelectedExtKey, txErr error) {
 	// Expect calls to gas functions only if there are no user defined values.
 	// And also set the expected gas and gas price for RLP encoding the expected tx.
@@ -115,11 +135,7 @@ func (s *TxQueueTestSuite) rlpEncodeTx(tx *QueuedTx, config *params.NodeConfig,
 }
 
 func (s *TxQueueTestSuite) TestCompleteTransaction() {
-	key, _ := crypto.GenerateKey()
-	selectedAccount := &account.SelectedExtKey{
-		Address:    account.FromAddress(TestConfig.Account1.Address),
-		AccountKey: &keystore.Key{PrivateKey: key},
-	}
+	selectedAccount := newSelectedAccount()
 	testCases := []struct {
 		name     string
 		gas      *hexutil.Uint64
@@ -149,6 +165,8 @@ func (s *TxQueueTestSuite) TestCompleteTransaction() {
 
 	for _, testCase := range testCases {
 		s.T().Run(testCase.name, func(t *testing.T) {
+			// every case gets a fresh manager and mocks, the previous ones are released first
+			s.TearDownTest()
 			s.SetupTest()
 			tx := Create(context.Background(), SendTxArgs{
 				From:     account.FromAddress(TestConfig.Account1.Address),
@@ -181,12 +199,1010 @@ func (s *TxQueueTestSuite) TestCompleteTransaction() {
 	}
 }
 
-func (s *TxQueueTestSuite) TestCompleteTransactionMultipleTimes() {
+func (s *TxQueueTestSuite) TestCompleteTransactionWithGasMultiplier() {
+	selectedAccount := newSelectedAccount()
+	estimatedGas := hexutil.Uint64(100000)
+	testCases := []struct {
+		name        string
+		ctx         context.Context
+		input       hexutil.Bytes
+		gasLimit    hexutil.Uint64
+		expectedGas hexutil.Uint64
+	}{
+		{
+			"configMultiplier",
+			context.Background(),
+			nil,
+			hexutil.Uint64(4700000),
+			hexutil.Uint64(150000),
+		},
+		{
+			"contextMultiplier",
+			context.WithValue(context.Background(), GasMultiplierKey, 2.0),
+			nil,
+			hexutil.Uint64(4700000),
+			hexutil.Uint64(200000),
+		},
+		{
+			"cappedByBlockGasLimit",
+			context.Background(),
+			nil,
+			hexutil.Uint64(120000),
+			hexutil.Uint64(120000),
+		},
+		{
+			"largeInputMultiplier",
+			context.Background(),
+			hexutil.Bytes{1, 2, 3, 4},
+			hexutil.Uint64(4700000),
+			hexutil.Uint64(300000),
+		},
+		{
+			"smallInputMultiplier",
+			context.Background(),
+			hexutil.Bytes{1, 2, 3},
+			hexutil.Uint64(4700000),
+			hexutil.Uint64(150000),
+		},
+	}
+
+	for _, testCase := range testCases {
+		s.T().Run(testCase.name, func(t *testing.T) {
+			// every case gets a fresh manager and mocks, the previous ones are released first
+			s.TearDownTest()
+			s.SetupTest()
+			s.manager.config.GasMultiplier = 1.5
+			s.manager.config.LargeInputGasMultiplier = 3
+			s.manager.config.LargeInputSize = 4
+			tx := Create(testCase.ctx, SendTxArgs{
+				From:     account.FromAddress(TestConfig.Account1.Address),
+				To:       account.ToAddress(TestConfig.Account2.Address),
+				GasPrice: testGasPrice,
+				Input:    testCase.input,
+			})
+			s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), selectedAccount.Address, gethrpc.PendingBlockNumber).Return(&testNonce, nil)
+			s.txServiceMock.EXPECT().EstimateGas(gomock.Any(), gomock.Any()).Return(estimatedGas, nil)
+			s.txServiceMock.EXPECT().GetBlockByNumber(gomock.Any(), gethrpc.LatestBlockNumber, false).Return(map[string]interface{}{
+				"gasLimit": testCase.gasLimit,
+			}, nil)
+			data := s.rlpEncodeTx(tx, s.nodeConfig, selectedAccount, &testNonce, testCase.expectedGas, (*big.Int)(testGasPrice))
+			s.txServiceMock.EXPECT().SendRawTransaction(gomock.Any(), data).Return(gethcommon.Hash{}, nil)
+
+			s.NoError(s.manager.QueueTransaction(tx))
+			hash, err := s.manager.CompleteTransaction(tx.ID, selectedAccount)
+			s.NoError(err)
+			rst := s.manager.WaitForTransaction(tx)
+			s.NoError(rst.Error)
+			s.Equal(hash, rst.Hash)
+		})
+	}
+}
+
+func (s *TxQueueTestSuite) TestFallbackGasPrice() {
+	selectedAccount := newSelectedAccount()
+	fallbackGasPrice := big.NewInt(20)
+	s.manager.config.FallbackGasPrice = fallbackGasPrice.Uint64()
+
+	tx := Create(context.Background(), SendTxArgs{
+		From: account.FromAddress(TestConfig.Account1.Address),
+		To:   account.ToAddress(TestConfig.Account2.Address),
+		Gas:  &testGas,
+	})
+	s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), selectedAccount.Address, gethrpc.PendingBlockNumber).Return(&testNonce, nil)
+	s.txServiceMock.EXPECT().GasPrice(gomock.Any()).Return(nil, errors.New("gas price is not available"))
+	data := s.rlpEncodeTx(tx, s.nodeConfig, selectedAccount, &testNonce, testGas, fallbackGasPrice)
+	s.txServiceMock.EXPECT().SendRawTransaction(gomock.Any(), data).Return(gethcommon.Hash{}, nil)
+
+	s.NoError(s.manager.QueueTransaction(tx))
+	_, err := s.manager.CompleteTransaction(tx.ID, selectedAccount)
+	s.NoError(err)
+	s.NoError(s.manager.WaitForTransaction(tx).Error)
+}
+
+func (s *TxQueueTestSuite) TestGasExceedsBlockLimit() {
+	selectedAccount := newSelectedAccount()
+	gas := hexutil.Uint64(testBlockGasLimit + 1)
+	complete := func() error {
+		tx := Create(context.Background(), SendTxArgs{
+			From:     account.FromAddress(TestConfig.Account1.Address),
+			To:       nil,
+			Gas:      &gas,
+			GasPrice: testGasPrice,
+			Input:    hexutil.Bytes{0x60},
+		})
+		s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), selectedAccount.Address, gethrpc.PendingBlockNumber).Return(&testNonce, nil)
+		s.NoError(s.manager.QueueTransaction(tx))
+		_, err := s.manager.CompleteTransaction(tx.ID, selectedAccount)
+		return err
+	}
+
+	// configured limit of the network
+	s.Equal(ErrGasExceedsBlockLimit, complete())
+
+	// gas limit of the latest block is fetched once
+	s.manager.config.MaxGasLimits = nil
+	s.txServiceMock.EXPECT().GetBlockByNumber(gomock.Any(), gethrpc.LatestBlockNumber, false).Return(map[string]interface{}{
+		"gasLimit": hexutil.Uint64(testBlockGasLimit),
+	}, nil).Times(1)
+	s.Equal(ErrGasExceedsBlockLimit, complete())
+	s.Equal(ErrGasExceedsBlockLimit, complete())
+}
+
+func (s *TxQueueTestSuite) TestLearnGasUsage() {
+	defer func(interval time.Duration) { GasUsagePollInterval = interval }(GasUsagePollInterval)
+	GasUsagePollInterval = time.Millisecond
+	s.manager.gasUsage = newGasUsageCache(1)
+
+	tx := Create(context.Background(), SendTxArgs{
+		From:  account.FromAddress(TestConfig.Account1.Address),
+		To:    account.ToAddress(TestConfig.Account2.Address),
+		Input: hexutil.Bytes{0xa9, 0x05, 0x9c, 0xbb},
+	})
+	hash := gethcommon.Hash{1}
+	gomock.InOrder(
+		s.txServiceMock.EXPECT().GetTransactionReceipt(gomock.Any(), hash).Return(nil, nil),
+		s.txServiceMock.EXPECT().GetTransactionReceipt(gomock.Any(), hash).Return(map[string]interface{}{
+			"gasUsed": hexutil.Uint64(50000),
+		}, nil),
+	)
+	s.manager.learnGasUsage(tx, hash, nil)
+
+	s.Equal(uint64(50000), s.manager.reconcileGasEstimate(tx, 40000))
+	s.Equal(uint64(60000), s.manager.reconcileGasEstimate(tx, 60000))
+
+	s.manager.ClearGasUsageCache()
+	s.Equal(uint64(40000), s.manager.reconcileGasEstimate(tx, 40000))
+}
+
+func (s *TxQueueTestSuite) TestNonceTooLowRetry() {
+	selectedAccount := newSelectedAccount()
+	nonceTooLowErr := errors.New("nonce too low")
+	refreshedNonce := testNonce + 1
+
+	testCases := []struct {
+		name    string
+		retries int
+		sendErr error
+		err     error
+	}{
+		{"retry succeeded", 1, nil, nil},
+		{"retry failed", 1, nonceTooLowErr, ErrNonceTooLow},
+		{"retries disabled", 0, nil, ErrNonceTooLow},
+	}
+	for _, tc := range testCases {
+		s.manager.config.NonceTooLowRetries = tc.retries
+		s.manager.localNonce.Delete(selectedAccount.Address)
+		tx := Create(context.Background(), SendTxArgs{
+			From:     account.FromAddress(TestConfig.Account1.Address),
+			To:       account.ToAddress(TestConfig.Account2.Address),
+			Gas:      &testGas,
+			GasPrice: testGasPrice,
+		})
+		gasPrice := (*big.Int)(testGasPrice)
+		calls := []*gomock.Call{
+			s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), selectedAccount.Address, gethrpc.PendingBlockNumber).Return(&testNonce, nil),
+			s.txServiceMock.EXPECT().SendRawTransaction(gomock.Any(), s.rlpEncodeTx(tx, s.nodeConfig, selectedAccount, &testNonce, testGas, gasPrice)).Return(gethcommon.Hash{}, nonceTooLowErr),
+		}
+		if tc.retries > 0 {
+			calls = append(calls,
+				s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), selectedAccount.Address, gethrpc.PendingBlockNumber).Return(&refreshedNonce, nil),
+				s.txServiceMock.EXPECT().SendRawTransaction(gomock.Any(), s.rlpEncodeTx(tx, s.nodeConfig, selectedAccount, &refreshedNonce, testGas, gasPrice)).Return(gethcommon.Hash{}, tc.sendErr),
+			)
+		}
+		gomock.InOrder(calls...)
+
+		s.NoError(s.manager.QueueTransaction(tx), tc.name)
+		_, err := s.manager.CompleteTransaction(tx.ID, selectedAccount)
+		s.Equal(tc.err, err, tc.name)
+		s.Equal(tc.err, s.manager.WaitForTransaction(tx).Error, tc.name)
+	}
+}
+
+func (s *TxQueueTestSuite) TestPauseBroadcast() {
+	selectedAccount := newSelectedAccount()
+	s.manager.PauseBroadcast()
+
+	var txs []*QueuedTx
+	var hashes []gethcommon.Hash
+	for i := 0; i < 2; i++ {
+		tx := Create(context.Background(), SendTxArgs{
+			From:     account.FromAddress(TestConfig.Account1.Address),
+			To:       account.ToAddress(TestConfig.Account2.Address),
+			Gas:      &testGas,
+			GasPrice: testGasPrice,
+		})
+		s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), selectedAccount.Address, gethrpc.PendingBlockNumber).Return(&testNonce, nil)
+		s.NoError(s.manager.QueueTransaction(tx))
+		// transaction is signed, but not sent and stays in the queue
+		rst := s.manager.CompleteTransactionResult(tx.ID, selectedAccount)
+		s.Equal(ErrBroadcastHeld, rst.Error)
+		s.Equal(StageBroadcast, rst.Stage)
+		s.True(s.manager.TransactionQueue().IsInprogress(tx.ID))
+		txs = append(txs, tx)
+		hashes = append(hashes, rst.Hash)
+	}
+	// local nonce is advanced only when held transactions are sent
+	_, ok := s.manager.localNonce.Load(selectedAccount.Address)
+	s.False(ok)
+
+	// held transactions are sent in nonce order
+	first := s.rlpEncodeTx(txs[0], s.nodeConfig, selectedAccount, &testNonce, testGas, (*big.Int)(testGasPrice))
+	nextNonce := testNonce + 1
+	second := s.rlpEncodeTx(txs[1], s.nodeConfig, selectedAccount, &nextNonce, testGas, (*big.Int)(testGasPrice))
+	gomock.InOrder(
+		s.txServiceMock.EXPECT().SendRawTransaction(gomock.Any(), first).Return(gethcommon.Hash{}, nil),
+		s.txServiceMock.EXPECT().SendRawTransaction(gomock.Any(), second).Return(gethcommon.Hash{}, nil),
+	)
+	results := s.manager.ResumeBroadcast()
+	s.Len(results, 2)
+	for i, hash := range hashes {
+		err, ok := results[hash]
+		s.True(ok)
+		s.NoError(err)
+		rst := s.manager.WaitForTransaction(txs[i])
+		s.NoError(rst.Error)
+		s.Equal(hash, rst.Hash)
+	}
+	nonce, ok := s.manager.localNonce.Load(selectedAccount.Address)
+	s.True(ok)
+	s.Equal(uint64(testNonce)+2, nonce)
+	s.Empty(s.manager.ResumeBroadcast())
+}
+
+func (s *TxQueueTestSuite) TestResumeBroadcastFailure() {
+	selectedAccount := newSelectedAccount()
+	s.manager.PauseBroadcast()
+
+	var txs []*QueuedTx
+	for i := 0; i < 2; i++ {
+		tx := Create(context.Background(), SendTxArgs{
+			From:     account.FromAddress(TestConfig.Account1.Address),
+			To:       account.ToAddress(TestConfig.Account2.Address),
+			Gas:      &testGas,
+			GasPrice: testGasPrice,
+		})
+		s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), selectedAccount.Address, gethrpc.PendingBlockNumber).Return(&testNonce, nil)
+		s.NoError(s.manager.QueueTransaction(tx))
+		s.Equal(ErrBroadcastHeld, s.manager.CompleteTransactionResult(tx.ID, selectedAccount).Error)
+		txs = append(txs, tx)
+	}
+
+	// following transaction of the account isn't sent after a failure
+	sendErr := errors.New("upstream failure")
+	s.txServiceMock.EXPECT().SendRawTransaction(gomock.Any(), gomock.Any()).Return(gethcommon.Hash{}, sendErr)
+	results := s.manager.ResumeBroadcast()
+	s.Len(results, 2)
+	s.EqualError(s.manager.WaitForTransaction(txs[0]).Error, sendErr.Error())
+	s.Equal(ErrPrecedingTxFailed, s.manager.WaitForTransaction(txs[1]).Error)
+	_, ok := s.manager.localNonce.Load(selectedAccount.Address)
+	s.False(ok)
+	s.Zero(s.manager.TransactionQueue().Count())
+}
+
+func (s *TxQueueTestSuite) TestDrainDiscardsHeldTransactions() {
+	selectedAccount := newSelectedAccount()
+	s.manager.PauseBroadcast()
+
+	tx := Create(context.Background(), SendTxArgs{
+		From:     account.FromAddress(TestConfig.Account1.Address),
+		To:       account.ToAddress(TestConfig.Account2.Address),
+		Gas:      &testGas,
+		GasPrice: testGasPrice,
+	})
+	s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), selectedAccount.Address, gethrpc.PendingBlockNumber).Return(&testNonce, nil)
+	s.NoError(s.manager.QueueTransaction(tx))
+	s.Equal(ErrBroadcastHeld, s.manager.CompleteTransactionResult(tx.ID, selectedAccount).Error)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	s.NoError(s.manager.Drain(ctx))
+	s.Equal(ErrShuttingDown, s.manager.WaitForTransaction(tx).Error)
+	s.Empty(s.manager.ResumeBroadcast())
+}
+
+func (s *TxQueueTestSuite) TestExportImportSignedResult() {
+	key, _ := crypto.GenerateKey()
+	otherKey, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	tx := Create(context.Background(), SendTxArgs{
+		From: from,
+		To:   account.ToAddress(TestConfig.Account2.Address),
+	})
+	s.NoError(s.manager.QueueTransaction(tx))
+
+	requests := s.manager.ExportPendingRequests()
+	s.Len(requests, 1)
+	s.Equal(tx.ID, requests[0].ID)
+	s.Equal(from, requests[0].Args.From)
+
+	sign := func(key *ecdsa.PrivateKey, value int64) hexutil.Bytes {
+		rawTx := types.NewTransaction(uint64(testNonce), *tx.Args.To, big.NewInt(value), uint64(testGas), (*big.Int)(testGasPrice), nil)
+		signedTx, err := types.SignTx(rawTx, types.NewEIP155Signer(big.NewInt(int64(s.nodeConfig.NetworkID))), key)
+		s.NoError(err)
+		data, err := rlp.EncodeToBytes(signedTx)
+		s.NoError(err)
+		return data
+	}
+
+	// transaction signed by another account is rejected and stays in the queue
+	_, err := s.manager.ImportSignedResult(context.Background(), tx.ID, sign(otherKey, 0))
+	s.Equal(ErrInvalidCompleteTxSender, err)
+	s.True(s.manager.TransactionQueue().Has(tx.ID))
+
+	// transaction which differs from the queued one is rejected
+	_, err = s.manager.ImportSignedResult(context.Background(), tx.ID, sign(key, 1))
+	s.Equal(ErrSignedTxMismatch, err)
+	s.True(s.manager.TransactionQueue().Has(tx.ID))
+
+	// transaction is held while broadcasting is paused
+	data := sign(key, 0)
+	s.manager.PauseBroadcast()
+	hash, err := s.manager.ImportSignedResult(context.Background(), tx.ID, data)
+	s.Equal(ErrBroadcastHeld, err)
+	s.True(s.manager.TransactionQueue().IsInprogress(tx.ID))
+
+	s.txServiceMock.EXPECT().SendRawTransaction(gomock.Any(), data).Return(gethcommon.Hash{}, nil)
+	results := s.manager.ResumeBroadcast()
+	s.NoError(results[hash])
+	rst := s.manager.WaitForTransaction(tx)
+	s.NoError(rst.Error)
+	s.Equal(hash, rst.Hash)
+	s.Empty(s.manager.ExportPendingRequests())
+	nonce, ok := s.manager.localNonce.Load(from)
+	s.True(ok)
+	s.Equal(uint64(testNonce)+1, nonce)
+}
+
+func (s *TxQueueTestSuite) TestImportSignedResultSkipIfMined() {
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	tx := Create(context.Background(), SendTxArgs{
+		From: from,
+		To:   account.ToAddress(TestConfig.Account2.Address),
+	})
+	s.NoError(s.manager.QueueTransaction(tx))
+
+	rawTx := types.NewTransaction(uint64(testNonce), *tx.Args.To, big.NewInt(0), uint64(testGas), (*big.Int)(testGasPrice), nil)
+	signedTx, err := types.SignTx(rawTx, types.NewEIP155Signer(big.NewInt(int64(s.nodeConfig.NetworkID))), key)
+	s.NoError(err)
+	data, err := rlp.EncodeToBytes(signedTx)
+	s.NoError(err)
+
+	// the same transaction is mined, it isn't sent again
+	confirmed := testNonce + 1
+	s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), from, gethrpc.LatestBlockNumber).Return(&confirmed, nil)
+	s.txServiceMock.EXPECT().GetTransactionReceipt(gomock.Any(), signedTx.Hash()).Return(map[string]interface{}{
+		"transactionHash": signedTx.Hash(),
+	}, nil)
+	ctx := context.WithValue(context.Background(), SkipIfMinedKey, true)
+	hash, err := s.manager.ImportSignedResult(ctx, tx.ID, data)
+	s.NoError(err)
+	s.Equal(signedTx.Hash(), hash)
+	s.Equal(hash, s.manager.WaitForTransaction(tx).Hash)
+}
+
+type selectedAccountProvider struct {
+	account *account.SelectedExtKey
+}
+
+func (p selectedAccountProvider) SelectedAccount() (*account.SelectedExtKey, error) {
+	return p.account, nil
+}
+
+type loggedOutProvider struct {
+	session gethcommon.Address
+	key     gethcommon.Address
+}
+
+func (p loggedOutProvider) SelectedAccount() (*account.SelectedExtKey, error) {
+	return nil, account.ErrNoAccountSelected
+}
+
+func (p loggedOutProvider) HasSession(address gethcommon.Address) bool {
+	return address == p.session
+}
+
+func (p loggedOutProvider) HasKey(address gethcommon.Address) bool {
+	return address == p.key
+}
+
+func (s *TxQueueTestSuite) TestRejectWithoutAccount() {
+	session := gethcommon.HexToAddress("0x01")
+	key := gethcommon.HexToAddress("0x02")
+	queue := func(from gethcommon.Address) error {
+		tx := Create(context.Background(), SendTxArgs{
+			From: from,
+			To:   account.ToAddress(TestConfig.Account2.Address),
+		})
+		err := s.manager.QueueTransaction(tx)
+		if err == nil {
+			s.NoError(s.manager.DiscardTransaction(tx.ID))
+		}
+		return err
+	}
+	s.manager.SetSelectedAccountProvider(loggedOutProvider{session: session, key: key})
+
+	// queued by default
+	s.NoError(queue(key))
+
+	s.manager.config.RejectWithoutAccount = true
+	s.NoError(queue(session))
+	s.Equal(account.ErrNoAccountSelected, queue(key))
+	s.manager.config.UseSenderAccount = true
+	s.NoError(queue(key))
+	s.Equal(account.ErrNoAccountSelected, queue(gethcommon.HexToAddress("0x03")))
+}
+
+// syncState reports the node synced if it lags by no more than gap blocks.
+type syncState struct {
+	gap uint64
+}
+
+func (p syncState) IsSynced(maxLag uint64) (bool, error) {
+	return p.gap <= maxLag, nil
+}
+
+func (s *TxQueueTestSuite) TestRefuseDuringSync() {
+	queue := func() error {
+		return s.manager.QueueTransaction(Create(context.Background(), SendTxArgs{
+			From: account.FromAddress(TestConfig.Account1.Address),
+			To:   account.ToAddress(TestConfig.Account2.Address),
+		}))
+	}
+	s.manager.config.MaxSyncLag = 10
+	s.manager.SetSyncStateProvider(syncState{gap: 11})
+	s.Equal(ErrNodeNotSynced, queue())
+
+	s.manager.config.AllowSendDuringSync = true
+	s.NoError(queue())
+
+	s.manager.config.AllowSendDuringSync = false
+	s.manager.SetSyncStateProvider(syncState{gap: 10})
+	s.NoError(queue())
+}
+
+func (s *TxQueueTestSuite) TestAutoComplete() {
+	selectedAccount := newSelectedAccount()
+	trusted := account.ToAddress(TestConfig.Account2.Address)
+	s.manager.SetSelectedAccountProvider(selectedAccountProvider{selectedAccount})
+	s.manager.config.AutoCompleteEnabled = true
+	s.manager.config.AutoCompleteRecipients = []gethcommon.Address{*trusted}
+
+	tx := Create(context.Background(), SendTxArgs{
+		From: selectedAccount.Address,
+		To:   trusted,
+	})
+	s.setupTransactionPoolAPI(tx, testNonce, testNonce, selectedAccount, nil)
+	s.NoError(s.manager.QueueTransaction(tx))
+	s.NoError(s.manager.WaitForTransaction(tx).Error)
+
+	// transactions to other recipients are left in the queue
+	other := gethcommon.HexToAddress("0x01")
+	tx = Create(context.Background(), SendTxArgs{
+		From: selectedAccount.Address,
+		To:   &other,
+	})
+	s.NoError(s.manager.QueueTransaction(tx))
+	s.True(s.manager.TransactionQueue().Has(tx.ID))
+	s.NoError(s.manager.DiscardTransaction(tx.ID))
+}
+
+func (s *TxQueueTestSuite) TestHighValueIsNotAutoCompleted() {
+	selectedAccount := newSelectedAccount()
+	trusted := account.ToAddress(TestConfig.Account2.Address)
+	s.manager.SetSelectedAccountProvider(selectedAccountProvider{selectedAccount})
+	s.manager.config.AutoCompleteEnabled = true
+	s.manager.config.AutoCompleteRecipients = []gethcommon.Address{*trusted}
+	s.manager.config.ValueLimits = map[uint64]params.ValueLimit{
+		params.RopstenNetworkID: {PerTransaction: big.NewInt(10)},
+	}
+
+	tx := Create(context.Background(), SendTxArgs{
+		From:  selectedAccount.Address,
+		To:    trusted,
+		Value: (*hexutil.Big)(big.NewInt(11)),
+	})
+	s.NoError(s.manager.QueueTransaction(tx))
+	s.True(s.manager.TransactionQueue().Has(tx.ID))
+	s.NoError(s.manager.DiscardTransaction(tx.ID))
+}
+
+type fiatPriceProvider struct {
+	price float64
+	err   error
+}
+
+func (p fiatPriceProvider) NativeTokenPrice(context.Context, uint64) (float64, error) {
+	return p.price, p.err
+}
+
+func (s *TxQueueTestSuite) TestFiatFeeCap() {
+	gas := hexutil.Uint64(21000)
+	// fee is 21000 gwei, i.e. 0.021 at price of 1000 per token
+	tx := Create(context.Background(), SendTxArgs{
+		From:     account.FromAddress(TestConfig.Account1.Address),
+		To:       account.ToAddress(TestConfig.Account2.Address),
+		Gas:      &gas,
+		GasPrice: (*hexutil.Big)(big.NewInt(1e9)),
+	})
+	s.manager.config.MaxFiatFee = 0.01
+
+	// cap is not enforced without a price provider
+	s.False(s.manager.isFeeOverFiatCap(tx))
+
+	s.manager.SetFiatPriceProvider(fiatPriceProvider{price: 1000})
+	s.True(s.manager.isFeeOverFiatCap(tx))
+	s.manager.config.MaxFiatFee = 0.05
+	s.False(s.manager.isFeeOverFiatCap(tx))
+
+	// suggested gas price is used if it is not set
+	tx.Args.GasPrice = nil
+	s.txServiceMock.EXPECT().GasPrice(gomock.Any()).Return(big.NewInt(1e10), nil)
+	s.True(s.manager.isFeeOverFiatCap(tx))
+
+	s.manager.SetFiatPriceProvider(fiatPriceProvider{err: errors.New("price is not available")})
+	s.txServiceMock.EXPECT().GasPrice(gomock.Any()).Return(big.NewInt(1e10), nil)
+	s.False(s.manager.isFeeOverFiatCap(tx))
+
+	// cap is not enforced if the check doesn't complete in time
+	s.manager.warningsTimeout = 50 * time.Millisecond
+	s.manager.SetFiatPriceProvider(fiatPriceProvider{price: 1000})
+	s.txServiceMock.EXPECT().GasPrice(gomock.Any()).Do(func(context.Context) {
+		time.Sleep(300 * time.Millisecond)
+	}).Return(big.NewInt(1e10), nil)
+	start := time.Now()
+	s.False(s.manager.isFeeOverFiatCap(tx))
+	s.True(time.Since(start) < 300*time.Millisecond, "fee check is not limited by timeout")
+}
+
+func (s *TxQueueTestSuite) TestMinGasPrice() {
+	selectedAccount := newSelectedAccount()
+	s.manager.config.MinGasPrices = map[uint64]uint64{params.RopstenNetworkID: 20}
+
+	testCases := []struct {
+		name     string
+		gasPrice int64
+		expected int64
+	}{
+		{"raised to minimum", 10, 20},
+		{"higher price is kept", 30, 30},
+	}
+	for _, tc := range testCases {
+		s.manager.localNonce.Delete(selectedAccount.Address)
+		tx := Create(context.Background(), SendTxArgs{
+			From:     account.FromAddress(TestConfig.Account1.Address),
+			To:       account.ToAddress(TestConfig.Account2.Address),
+			Gas:      &testGas,
+			GasPrice: (*hexutil.Big)(big.NewInt(tc.gasPrice)),
+		})
+		s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), selectedAccount.Address, gethrpc.PendingBlockNumber).Return(&testNonce, nil)
+		data := s.rlpEncodeTx(tx, s.nodeConfig, selectedAccount, &testNonce, testGas, big.NewInt(tc.expected))
+		s.txServiceMock.EXPECT().SendRawTransaction(gomock.Any(), data).Return(gethcommon.Hash{}, nil)
+
+		s.NoError(s.manager.QueueTransaction(tx), tc.name)
+		_, err := s.manager.CompleteTransaction(tx.ID, selectedAccount)
+		s.NoError(err, tc.name)
+		s.Equal(big.NewInt(tc.gasPrice), tx.Args.GasPrice.ToInt(), "%s: user-provided price is not modified", tc.name)
+	}
+}
+
+func (s *TxQueueTestSuite) TestSendRawTransaction() {
 	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	sign := func(chainID int64) hexutil.Bytes {
+		rawTx := types.NewTransaction(uint64(testNonce), gethcommon.HexToAddress("0x01"), big.NewInt(0), uint64(testGas), (*big.Int)(testGasPrice), nil)
+		signedTx, err := types.SignTx(rawTx, types.NewEIP155Signer(big.NewInt(chainID)), key)
+		s.NoError(err)
+		data, err := rlp.EncodeToBytes(signedTx)
+		s.NoError(err)
+		return data
+	}
+
+	_, err := s.manager.SendRawTransaction(context.Background(), sign(int64(params.MainNetworkID)))
+	s.Equal(ErrInvalidChainID, err)
+
+	data := sign(int64(s.nodeConfig.NetworkID))
+	s.txServiceMock.EXPECT().SendRawTransaction(gomock.Any(), data).Return(gethcommon.Hash{}, nil)
+	hash, err := s.manager.SendRawTransaction(context.Background(), data)
+	s.NoError(err)
+	s.NotEqual(gethcommon.Hash{}, hash)
+	nonce, ok := s.manager.localNonce.Load(from)
+	s.True(ok)
+	s.Equal(uint64(testNonce)+1, nonce)
+}
+
+func (s *TxQueueTestSuite) TestSendRawTransactionSignals() {
+	var (
+		failed []ReturnSendTransactionEvent
+		done   []TransactionDoneEvent
+	)
+	remove := signal.AddHandler(func(envelope signal.Envelope) {
+		switch event := envelope.Event.(type) {
+		case ReturnSendTransactionEvent:
+			failed = append(failed, event)
+		case TransactionDoneEvent:
+			done = append(done, event)
+		}
+	})
+	defer remove()
+	s.manager.notify = true
+
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	rawTx := types.NewTransaction(uint64(testNonce), gethcommon.HexToAddress("0x01"), big.NewInt(0), uint64(testGas), (*big.Int)(testGasPrice), nil)
+	signedTx, err := types.SignTx(rawTx, types.NewEIP155Signer(big.NewInt(int64(s.nodeConfig.NetworkID))), key)
+	s.NoError(err)
+	data, err := rlp.EncodeToBytes(signedTx)
+	s.NoError(err)
+
+	s.txServiceMock.EXPECT().SendRawTransaction(gomock.Any(), hexutil.Bytes(data)).Return(gethcommon.Hash{}, nil)
+	_, err = s.manager.SendRawTransaction(context.Background(), data)
+	s.NoError(err)
+	s.Empty(failed)
+	s.Require().Len(done, 1)
+	s.Equal(signedTx.Hash(), done[0].Hash)
+	s.Empty(done[0].ErrorMessage)
+
+	sendErr := errors.New("nonce too low")
+	s.txServiceMock.EXPECT().SendRawTransaction(gomock.Any(), hexutil.Bytes(data)).Return(gethcommon.Hash{}, sendErr)
+	_, err = s.manager.SendRawTransaction(context.Background(), data)
+	s.EqualError(err, sendErr.Error())
+	s.Require().Len(failed, 1)
+	s.Equal(from, failed[0].Args.From)
+	s.Equal(uint64(testNonce), uint64(*failed[0].Args.Nonce))
+	s.Equal(sendErr.Error(), failed[0].ErrorMessage)
+	s.Require().Len(done, 2)
+	s.Equal(failed[0].ID, done[1].ID)
+	s.Equal(gethcommon.Hash{}, done[1].Hash)
+	s.Equal(sendErr.Error(), done[1].ErrorMessage)
+}
+
+func (s *TxQueueTestSuite) TestSendRawTransactionSkipIfMined() {
+	key, _ := crypto.GenerateKey()
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	rawTx := types.NewTransaction(uint64(testNonce), gethcommon.HexToAddress("0x01"), big.NewInt(0), uint64(testGas), (*big.Int)(testGasPrice), nil)
+	signedTx, err := types.SignTx(rawTx, types.NewEIP155Signer(big.NewInt(int64(s.nodeConfig.NetworkID))), key)
+	s.NoError(err)
+	data, err := rlp.EncodeToBytes(signedTx)
+	s.NoError(err)
+	ctx := context.WithValue(context.Background(), SkipIfMinedKey, true)
+	confirmed := testNonce + 1
+
+	// the same transaction is mined, it isn't sent again
+	s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), from, gethrpc.LatestBlockNumber).Return(&confirmed, nil)
+	s.txServiceMock.EXPECT().GetTransactionReceipt(gomock.Any(), signedTx.Hash()).Return(map[string]interface{}{
+		"transactionHash": signedTx.Hash(),
+	}, nil)
+	hash, err := s.manager.SendRawTransaction(ctx, data)
+	s.NoError(err)
+	s.Equal(signedTx.Hash(), hash)
+
+	// another transaction with the same nonce is mined
+	s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), from, gethrpc.LatestBlockNumber).Return(&confirmed, nil)
+	s.txServiceMock.EXPECT().GetTransactionReceipt(gomock.Any(), signedTx.Hash()).Return(nil, nil)
+	_, err = s.manager.SendRawTransaction(ctx, data)
+	s.Equal(ErrNonceAlreadyMined, err)
+
+	// nonce isn't confirmed yet
+	s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), from, gethrpc.LatestBlockNumber).Return(&testNonce, nil)
+	s.txServiceMock.EXPECT().SendRawTransaction(gomock.Any(), hexutil.Bytes(data)).Return(gethcommon.Hash{}, nil)
+	hash, err = s.manager.SendRawTransaction(ctx, data)
+	s.NoError(err)
+	s.Equal(signedTx.Hash(), hash)
+}
+
+func (s *TxQueueTestSuite) TestNonceGap() {
+	selectedAccount := newSelectedAccount()
+	confirmedNonce := testNonce - 1
+	s.manager.localNonce.Store(selectedAccount.Address, uint64(testNonce)+2)
+
+	s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), selectedAccount.Address, gethrpc.LatestBlockNumber).Return(&confirmedNonce, nil)
+	s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), selectedAccount.Address, gethrpc.PendingBlockNumber).Return(&testNonce, nil)
+	status, err := s.manager.NonceStatus(context.Background(), selectedAccount.Address)
+	s.NoError(err)
+	s.Equal(NonceStatus{Confirmed: uint64(confirmedNonce), Pending: uint64(testNonce), Local: uint64(testNonce) + 2}, status)
+	s.True(status.HasGap())
+
+	s.manager.config.RefuseOnNonceGap = true
+	tx := Create(context.Background(), SendTxArgs{
+		From:     selectedAccount.Address,
+		To:       account.ToAddress(TestConfig.Account2.Address),
+		Gas:      &testGas,
+		GasPrice: testGasPrice,
+	})
+	s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), selectedAccount.Address, gethrpc.PendingBlockNumber).Return(&testNonce, nil)
+	s.NoError(s.manager.QueueTransaction(tx))
+	_, err = s.manager.CompleteTransaction(tx.ID, selectedAccount)
+	s.Equal(ErrNonceGap, err)
+}
+
+func (s *TxQueueTestSuite) TestPendingTransactionIsNotGap() {
+	selectedAccount := newSelectedAccount()
+	s.manager.config.RefuseOnNonceGap = true
+	// previous transaction is pending, but not mined yet
+	s.manager.localNonce.Store(selectedAccount.Address, uint64(testNonce))
+
+	tx := Create(context.Background(), SendTxArgs{
+		From:     selectedAccount.Address,
+		To:       account.ToAddress(TestConfig.Account2.Address),
+		Gas:      &testGas,
+		GasPrice: testGasPrice,
+	})
+	s.setupTransactionPoolAPI(tx, testNonce, testNonce, selectedAccount, nil)
+	s.NoError(s.manager.QueueTransaction(tx))
+	_, err := s.manager.CompleteTransaction(tx.ID, selectedAccount)
+	s.NoError(err)
+}
+
+type keySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+func (s keySigner) SignTx(account gethcommon.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.NewEIP155Signer(chainID), s.key)
+}
+
+func (s *TxQueueTestSuite) TestExternalSigner() {
+	externalKey, _ := crypto.GenerateKey()
 	selectedAccount := &account.SelectedExtKey{
-		Address:    account.FromAddress(TestConfig.Account1.Address),
-		AccountKey: &keystore.Key{PrivateKey: key},
+		Address: account.FromAddress(TestConfig.Account1.Address),
+	}
+	s.manager.RegisterSigner(selectedAccount.Address, keySigner{externalKey})
+
+	tx := Create(context.Background(), SendTxArgs{
+		From: selectedAccount.Address,
+		To:   account.ToAddress(TestConfig.Account2.Address),
+	})
+	// transaction is expected to be signed by the external key
+	s.setupTransactionPoolAPI(tx, testNonce, testNonce, &account.SelectedExtKey{
+		Address:    selectedAccount.Address,
+		AccountKey: &keystore.Key{PrivateKey: externalKey},
+	}, nil)
+	s.NoError(s.manager.QueueTransaction(tx))
+	_, err := s.manager.CompleteTransaction(tx.ID, selectedAccount)
+	s.NoError(err)
+	s.NoError(s.manager.WaitForTransaction(tx).Error)
+}
+
+func (s *TxQueueTestSuite) TestDiscardSuperseded() {
+	selectedAccount := newSelectedAccount()
+	s.manager.config.DiscardSuperseded = true
+
+	newTx := func(nonce hexutil.Uint64) *QueuedTx {
+		return Create(context.Background(), SendTxArgs{
+			From:  selectedAccount.Address,
+			To:    account.ToAddress(TestConfig.Account2.Address),
+			Nonce: &nonce,
+		})
+	}
+	tx := newTx(testNonce)
+	superseded := newTx(testNonce)
+	other := newTx(testNonce + 1)
+	for _, queuedTx := range []*QueuedTx{tx, superseded, other} {
+		s.NoError(s.manager.QueueTransaction(queuedTx))
+	}
+
+	s.setupTransactionPoolAPI(tx, testNonce, testNonce, selectedAccount, nil)
+	_, err := s.manager.CompleteTransaction(tx.ID, selectedAccount)
+	s.NoError(err)
+	s.NoError(s.manager.WaitForTransaction(tx).Error)
+	s.Equal(ErrQueuedTxSuperseded, s.manager.WaitForTransaction(superseded).Error)
+	s.True(s.manager.TransactionQueue().Has(other.ID))
+}
+
+func (s *TxQueueTestSuite) TestApplyEIP1559Fees() {
+	newTx := func(gasPrice, maxFee, priorityFee int64) *QueuedTx {
+		args := SendTxArgs{
+			From:                 account.FromAddress(TestConfig.Account1.Address),
+			To:                   account.ToAddress(TestConfig.Account2.Address),
+			MaxFeePerGas:         (*hexutil.Big)(big.NewInt(maxFee)),
+			MaxPriorityFeePerGas: (*hexutil.Big)(big.NewInt(priorityFee)),
+		}
+		if gasPrice > 0 {
+			args.GasPrice = (*hexutil.Big)(big.NewInt(gasPrice))
+		}
+		return Create(context.Background(), args)
+	}
+	legacyBlock := map[string]interface{}{"gasLimit": hexutil.Uint64(8000000)}
+	londonBlock := map[string]interface{}{"gasLimit": hexutil.Uint64(8000000), "baseFeePerGas": (*hexutil.Big)(big.NewInt(10))}
+
+	// legacy chain, suggested gas price is used
+	tx := newTx(0, 30, 2)
+	s.txServiceMock.EXPECT().GetBlockByNumber(gomock.Any(), gethrpc.LatestBlockNumber, false).Return(legacyBlock, nil)
+	s.txServiceMock.EXPECT().GasPrice(gomock.Any()).Return(big.NewInt(20), nil)
+	downgraded, err := s.manager.applyEIP1559Fees(tx)
+	s.NoError(err)
+	s.True(downgraded)
+	s.Equal(big.NewInt(20), tx.Args.GasPrice.ToInt())
+	s.Nil(tx.Args.MaxFeePerGas)
+
+	// legacy chain, suggested gas price is capped by max fee
+	tx = newTx(0, 30, 2)
+	s.txServiceMock.EXPECT().GetBlockByNumber(gomock.Any(), gethrpc.LatestBlockNumber, false).Return(legacyBlock, nil)
+	s.txServiceMock.EXPECT().GasPrice(gomock.Any()).Return(big.NewInt(50), nil)
+	_, err = s.manager.applyEIP1559Fees(tx)
+	s.NoError(err)
+	s.Equal(big.NewInt(30), tx.Args.GasPrice.ToInt())
+
+	// legacy chain, transaction is refused
+	s.manager.config.RejectEIP1559OnLegacyChains = true
+	s.txServiceMock.EXPECT().GetBlockByNumber(gomock.Any(), gethrpc.LatestBlockNumber, false).Return(legacyBlock, nil)
+	_, err = s.manager.applyEIP1559Fees(newTx(0, 30, 2))
+	s.Equal(ErrEIP1559NotSupported, err)
+
+	// chain with base fee
+	tx = newTx(0, 30, 2)
+	s.txServiceMock.EXPECT().GetBlockByNumber(gomock.Any(), gethrpc.LatestBlockNumber, false).Return(londonBlock, nil)
+	downgraded, err = s.manager.applyEIP1559Fees(tx)
+	s.NoError(err)
+	s.False(downgraded)
+	s.Equal(big.NewInt(12), tx.Args.GasPrice.ToInt())
+
+	// derived price is not remembered as chosen by user
+	s.manager.storeGasPrice(tx)
+	_, ok := s.manager.LastGasPrice(tx.Args.From)
+	s.False(ok)
+
+	// explicit gas price takes precedence
+	tx = newTx(5, 30, 2)
+	downgraded, err = s.manager.applyEIP1559Fees(tx)
+	s.NoError(err)
+	s.False(downgraded)
+	s.Equal(big.NewInt(5), tx.Args.GasPrice.ToInt())
+	s.manager.storeGasPrice(tx)
+	price, ok := s.manager.LastGasPrice(tx.Args.From)
+	s.True(ok)
+	s.Equal(big.NewInt(5), price.ToInt())
+}
+
+func (s *TxQueueTestSuite) TestQueueWarnings() {
+	to := account.ToAddress(TestConfig.Account2.Address)
+	tx := Create(context.Background(), SendTxArgs{
+		From:     account.FromAddress(TestConfig.Account1.Address),
+		To:       to,
+		GasPrice: testGasPrice,
+	})
+	s.txServiceMock.EXPECT().GasPrice(gomock.Any()).Return(big.NewInt(20), nil)
+	s.txServiceMock.EXPECT().GetCode(gomock.Any(), *to, gethrpc.LatestBlockNumber).Return(hexutil.Bytes{0x60}, nil)
+	s.Equal([]string{WarningGasPriceBelowSuggested, WarningRecipientIsContract}, s.manager.queueWarnings(tx))
+
+	// result of the code check is reused
+	s.txServiceMock.EXPECT().GasPrice(gomock.Any()).Return(big.NewInt(20), nil)
+	s.Equal([]string{WarningGasPriceBelowSuggested, WarningRecipientIsContract}, s.manager.queueWarnings(tx))
+
+	// contract calls are reported only if recipient has no code
+	tx.Args.GasPrice = nil
+	tx.Args.Input = hexutil.Bytes{0x01}
+	s.Empty(s.manager.queueWarnings(tx))
+
+	other := gethcommon.HexToAddress("0x02")
+	tx.Args.To = &other
+	s.txServiceMock.EXPECT().GetCode(gomock.Any(), other, gethrpc.LatestBlockNumber).Return(hexutil.Bytes{}, nil)
+	s.Equal([]string{WarningRecipientHasNoCode}, s.manager.queueWarnings(tx))
+	s.Equal([]string{WarningRecipientHasNoCode}, s.manager.queueWarnings(tx))
+
+	// failed checks are skipped
+	failing := gethcommon.HexToAddress("0x03")
+	tx.Args.To = &failing
+	tx.Args.GasPrice = testGasPrice
+	s.txServiceMock.EXPECT().GasPrice(gomock.Any()).Return(nil, errors.New("gas price is not available"))
+	s.txServiceMock.EXPECT().GetCode(gomock.Any(), failing, gethrpc.LatestBlockNumber).Return(nil, errors.New("code is not available"))
+	s.Empty(s.manager.queueWarnings(tx))
+}
+
+func (s *TxQueueTestSuite) TestQueueWarningsTimeout() {
+	s.manager.warningsTimeout = 50 * time.Millisecond
+	to := account.ToAddress(TestConfig.Account2.Address)
+	tx := Create(context.Background(), SendTxArgs{
+		From:     account.FromAddress(TestConfig.Account1.Address),
+		To:       to,
+		GasPrice: testGasPrice,
+	})
+	slow := func(context.Context) { time.Sleep(300 * time.Millisecond) }
+	s.txServiceMock.EXPECT().GasPrice(gomock.Any()).Do(slow).Return(big.NewInt(20), nil)
+	s.txServiceMock.EXPECT().GetCode(gomock.Any(), *to, gethrpc.LatestBlockNumber).Return(hexutil.Bytes{0x60}, nil)
+
+	// checks which don't complete in time are skipped
+	start := time.Now()
+	s.Equal([]string{WarningRecipientIsContract}, s.manager.queueWarnings(tx))
+	s.True(time.Since(start) < 300*time.Millisecond, "warnings are not limited by timeout")
+}
+
+func (s *TxQueueTestSuite) TestSelfAndDustWarnings() {
+	s.manager.config.DustThresholds = map[uint64]uint64{params.RopstenNetworkID: 100}
+	from := account.FromAddress(TestConfig.Account1.Address)
+	tx := Create(context.Background(), SendTxArgs{
+		From:  from,
+		To:    &from,
+		Value: (*hexutil.Big)(big.NewInt(99)),
+		Input: hexutil.Bytes{0x01},
+	})
+	s.txServiceMock.EXPECT().GetCode(gomock.Any(), gomock.Any(), gethrpc.LatestBlockNumber).Return(hexutil.Bytes{0x60}, nil).AnyTimes()
+	s.Equal([]string{WarningSelfTransaction, WarningDustValue}, s.manager.queueWarnings(tx))
+
+	// zero value and value at the threshold are not dust
+	tx.Args.To = account.ToAddress(TestConfig.Account2.Address)
+	for _, value := range []int64{0, 100} {
+		tx.Args.Value = (*hexutil.Big)(big.NewInt(value))
+		s.Empty(s.manager.queueWarnings(tx))
+	}
+}
+
+func (s *TxQueueTestSuite) TestZeroValueContractCall() {
+	selectedAccount := newSelectedAccount()
+	args, err := s.manager.rpcCalltoSendTxArgs(map[string]interface{}{
+		"from":  TestConfig.Account1.Address,
+		"to":    TestConfig.Account2.Address,
+		"input": "0xa9059cbb",
+	})
+	s.Require().NoError(err)
+	s.Require().NotNil(args.Value)
+	s.Equal(0, args.Value.ToInt().Sign())
+
+	tx := Create(context.Background(), args)
+	s.setupTransactionPoolAPI(tx, testNonce, testNonce, selectedAccount, nil)
+	s.NoError(s.manager.QueueTransaction(tx))
+	_, err = s.manager.CompleteTransaction(tx.ID, selectedAccount)
+	s.NoError(err)
+	s.NoError(s.manager.WaitForTransaction(tx).Error)
+}
+
+func (s *TxQueueTestSuite) TestRPCCallAddresses() {
+	from := TestConfig.Account1.Address
+	for _, to := range []interface{}{"0x123", "not an address", "0xzz" + TestConfig.Account2.Address[4:], 42} {
+		_, err := s.manager.rpcCalltoSendTxArgs(map[string]interface{}{"from": from, "to": to})
+		s.Equal(rpc.ErrInvalidToAddress, err, "to: %v", to)
+	}
+	for _, from := range []interface{}{"0x123", "not an address", nil} {
+		_, err := s.manager.rpcCalltoSendTxArgs(map[string]interface{}{"from": from, "to": TestConfig.Account2.Address})
+		s.Equal(rpc.ErrInvalidFromAddress, err, "from: %v", from)
+	}
+
+	// recipient is missing for contract creation
+	args, err := s.manager.rpcCalltoSendTxArgs(map[string]interface{}{"from": from, "data": "0x60"})
+	s.NoError(err)
+	s.Nil(args.To)
+}
+
+func (s *TxQueueTestSuite) TestSendTransactionRPCHandlerCanceled() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := s.manager.SendTransactionRPCHandler(ctx, map[string]interface{}{
+		"from": TestConfig.Account1.Address,
+		"to":   TestConfig.Account2.Address,
+	})
+	// transaction which isn't completed yet is never sent after the caller gave up
+	s.Equal(ErrQueuedTxDiscarded, err)
+	s.Zero(s.manager.TransactionQueue().Count())
+}
+
+func (s *TxQueueTestSuite) TestDrain() {
+	newTx := func() *QueuedTx {
+		return Create(context.Background(), SendTxArgs{
+			From: account.FromAddress(TestConfig.Account1.Address),
+			To:   account.ToAddress(TestConfig.Account2.Address),
+		})
 	}
+	inprogress := newTx()
+	queued := newTx()
+	s.NoError(s.manager.QueueTransaction(inprogress))
+	s.NoError(s.manager.QueueTransaction(queued))
+	s.NoError(s.manager.TransactionQueue().LockInprogress(inprogress.ID))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	s.Equal(context.DeadlineExceeded, s.manager.Drain(ctx))
+	s.Equal(ErrShuttingDown, s.manager.WaitForTransaction(queued).Error)
+	s.True(s.manager.TransactionQueue().Has(inprogress.ID))
+	s.Equal(ErrShuttingDown, s.manager.QueueTransaction(newTx()))
+
+	// once transaction is completed, drain returns right away
+	s.manager.txDone(inprogress, gethcommon.Hash{}, nil)
+	s.NoError(s.manager.Drain(context.Background()))
+}
+
+func (s *TxQueueTestSuite) TestCompleteTransactionMultipleTimes() {
+	selectedAccount := newSelectedAccount()
 
 	tx := Create(context.Background(), SendTxArgs{
 		From: account.FromAddress(TestConfig.Account1.Address),
@@ -254,6 +1270,111 @@ func (s *TxQueueTestSuite) TestAccountMismatch() {
 	s.True(s.manager.TransactionQueue().Has(tx.ID))
 }
 
+func (s *TxQueueTestSuite) TestCompleteTransactionResultStage() {
+	selectedAccount := newSelectedAccount()
+	queue := func() *QueuedTx {
+		tx := Create(context.Background(), SendTxArgs{
+			From: account.FromAddress(TestConfig.Account1.Address),
+			To:   account.ToAddress(TestConfig.Account2.Address),
+			Gas:  &testGas,
+		})
+		s.NoError(s.manager.QueueTransaction(tx))
+		return tx
+	}
+
+	// sender mismatch fails before anything is prepared
+	tx := queue()
+	result := s.manager.CompleteTransactionResult(tx.ID, &account.SelectedExtKey{
+		Address: account.FromAddress(TestConfig.Account2.Address),
+	})
+	s.Equal(ErrInvalidCompleteTxSender, result.Error)
+	s.Equal(StageUnlock, result.Stage)
+
+	// gas price is not available
+	s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), selectedAccount.Address, gethrpc.PendingBlockNumber).Return(&testNonce, nil)
+	s.txServiceMock.EXPECT().GasPrice(gomock.Any()).Return(nil, errors.New("gas price is not available"))
+	result = s.manager.CompleteTransactionResult(tx.ID, selectedAccount)
+	s.Error(result.Error)
+	s.Equal(StageEstimate, result.Stage)
+
+	// node refused signed transaction
+	tx = queue()
+	s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), selectedAccount.Address, gethrpc.PendingBlockNumber).Return(&testNonce, nil)
+	s.txServiceMock.EXPECT().GasPrice(gomock.Any()).Return((*big.Int)(testGasPrice), nil)
+	s.txServiceMock.EXPECT().SendRawTransaction(gomock.Any(), gomock.Any()).Return(gethcommon.Hash{}, errors.New("insufficient funds"))
+	result = s.manager.CompleteTransactionResult(tx.ID, selectedAccount)
+	s.Error(result.Error)
+	s.Equal(StageBroadcast, result.Stage)
+}
+
+func (s *TxQueueTestSuite) TestSigner() {
+	s.Equal(types.NewEIP155Signer(big.NewInt(params.RopstenNetworkID)), s.manager.signer())
+	s.manager.config.DisableReplayProtection = true
+	s.Equal(types.HomesteadSigner{}, s.manager.signer())
+}
+
+func (s *TxQueueTestSuite) TestSwitchNetwork() {
+	address := account.FromAddress(TestConfig.Account1.Address)
+	s.manager.localNonce.Store(address, uint64(testNonce))
+
+	s.manager.SwitchNetwork(params.RinkebyNetworkID)
+	s.Equal(types.NewEIP155Signer(big.NewInt(params.RinkebyNetworkID)), s.manager.signer())
+	_, ok := s.manager.localNonce.Load(address)
+	s.False(ok, "nonce of the previous network must be forgotten")
+}
+
+func (s *TxQueueTestSuite) TestEnqueueHandler() {
+	var queued int
+	remove := signal.AddHandler(func(envelope signal.Envelope) {
+		if envelope.Type == EventTransactionQueued {
+			queued++
+		}
+	})
+	defer remove()
+	s.manager.notify = true
+
+	var handled []*QueuedTx
+	s.manager.SetEnqueueHandler(func(tx *QueuedTx) {
+		handled = append(handled, tx)
+	})
+	tx := Create(context.Background(), SendTxArgs{
+		From: account.FromAddress(TestConfig.Account1.Address),
+		To:   account.ToAddress(TestConfig.Account2.Address),
+	})
+	s.NoError(s.manager.QueueTransaction(tx))
+	s.Equal([]*QueuedTx{tx}, handled)
+	s.Zero(queued, "handled transaction must not be signaled to the app")
+
+	s.manager.SetEnqueueHandler(nil)
+	s.txServiceMock.EXPECT().GetCode(gomock.Any(), gomock.Any(), gethrpc.LatestBlockNumber).Return(hexutil.Bytes{}, nil)
+	s.NoError(s.manager.QueueTransaction(Create(context.Background(), SendTxArgs{
+		From: account.FromAddress(TestConfig.Account1.Address),
+		To:   account.ToAddress(TestConfig.Account2.Address),
+	})))
+	s.Len(handled, 1)
+	s.Equal(1, queued)
+}
+
+func (s *TxQueueTestSuite) TestEmptyContractCode() {
+	tx := Create(context.Background(), SendTxArgs{
+		From: account.FromAddress(TestConfig.Account1.Address),
+	})
+	s.Equal(ErrEmptyContractCode, s.manager.QueueTransaction(tx))
+
+	s.manager.config.AllowEmptyContractCode = true
+	s.NoError(s.manager.QueueTransaction(tx))
+}
+
+func (s *TxQueueTestSuite) TestInputTooLarge() {
+	s.manager.config.MaxInputSize = 4
+	tx := Create(context.Background(), SendTxArgs{
+		From:  account.FromAddress(TestConfig.Account1.Address),
+		Input: hexutil.Bytes{0x01, 0x02, 0x03, 0x04, 0x05},
+	})
+	s.Equal(ErrInputTooLarge, s.manager.QueueTransaction(tx))
+	s.False(s.manager.TransactionQueue().Has(tx.ID))
+}
+
 func (s *TxQueueTestSuite) TestDiscardTransaction() {
 	tx := Create(context.Background(), SendTxArgs{
 		From: account.FromAddress(TestConfig.Account1.Address),
@@ -274,6 +1395,82 @@ func (s *TxQueueTestSuite) TestDiscardTransaction() {
 	s.NoError(WaitClosed(w, time.Second))
 }
 
+func (s *TxQueueTestSuite) TestDiscardAllTransactions() {
+	var txs []*QueuedTx
+	for i := 0; i < 3; i++ {
+		tx := Create(context.Background(), SendTxArgs{
+			From: account.FromAddress(TestConfig.Account1.Address),
+			To:   account.ToAddress(TestConfig.Account2.Address),
+		})
+		s.NoError(s.manager.QueueTransaction(tx))
+		txs = append(txs, tx)
+	}
+	// transaction which is being completed is not discarded
+	s.NoError(s.manager.TransactionQueue().LockInprogress(txs[2].ID))
+
+	results := s.manager.DiscardAllTransactions()
+	s.Equal(map[string]error{
+		txs[0].ID: nil,
+		txs[1].ID: nil,
+		txs[2].ID: ErrQueuedTxInProgress,
+	}, results)
+	for _, tx := range txs[:2] {
+		s.Equal(ErrQueuedTxDiscarded, s.manager.WaitForTransaction(tx).Error)
+	}
+	s.True(s.manager.TransactionQueue().Has(txs[2].ID))
+
+	// discarded transactions can't be completed
+	_, err := s.manager.CompleteTransaction(txs[0].ID, nil)
+	s.Equal(ErrQueuedTxIDNotFound, err)
+	s.NoError(s.manager.TransactionQueue().Done(txs[2].ID, gethcommon.Hash{}, ErrQueuedTxDiscarded))
+}
+
+func (s *TxQueueTestSuite) TestFailTransaction() {
+	tx := Create(context.Background(), SendTxArgs{
+		From: account.FromAddress(TestConfig.Account1.Address),
+		To:   account.ToAddress(TestConfig.Account2.Address),
+	})
+	s.NoError(s.manager.QueueTransaction(tx))
+
+	// transaction is kept in the queue after a transient error
+	s.NoError(s.manager.TransactionQueue().Done(tx.ID, gethcommon.Hash{}, keystore.ErrDecrypt))
+	s.True(s.manager.TransactionQueue().Has(tx.ID))
+
+	s.NoError(s.manager.FailTransaction(tx.ID, keystore.ErrDecrypt))
+	s.Equal(keystore.ErrDecrypt, s.manager.WaitForTransaction(tx).Error)
+	s.False(s.manager.TransactionQueue().Has(tx.ID))
+	s.Equal(ErrQueuedTxIDNotFound, s.manager.FailTransaction(tx.ID, keystore.ErrDecrypt))
+}
+
+func (s *TxQueueTestSuite) TestDiscardTransactionsFrom() {
+	loggedOut := account.FromAddress(TestConfig.Account1.Address)
+	var loggedOutTxs []*QueuedTx
+	for i := 0; i < 2; i++ {
+		tx := Create(context.Background(), SendTxArgs{
+			From: loggedOut,
+			To:   account.ToAddress(TestConfig.Account2.Address),
+		})
+		s.NoError(s.manager.QueueTransaction(tx))
+		loggedOutTxs = append(loggedOutTxs, tx)
+	}
+	otherTx := Create(context.Background(), SendTxArgs{
+		From: account.FromAddress(TestConfig.Account2.Address),
+		To:   account.ToAddress(TestConfig.Account1.Address),
+	})
+	s.NoError(s.manager.QueueTransaction(otherTx))
+	s.Equal(3, s.manager.TransactionQueue().Count())
+
+	s.manager.DiscardTransactionsFrom(loggedOut, ErrAccountLoggedOut)
+
+	for _, tx := range loggedOutTxs {
+		rst := s.manager.WaitForTransaction(tx)
+		s.Equal(ErrAccountLoggedOut, rst.Error)
+		s.False(s.manager.TransactionQueue().Has(tx.ID))
+	}
+	s.Equal(1, s.manager.TransactionQueue().Count())
+	s.True(s.manager.TransactionQueue().Has(otherTx.ID))
+}
+
 func (s *TxQueueTestSuite) TestCompletionTimedOut() {
 	tx := Create(context.Background(), SendTxArgs{
 		From: account.FromAddress(TestConfig.Account1.Address),
@@ -294,11 +1491,7 @@ func (s *TxQueueTestSuite) TestCompletionTimedOut() {
 // as the last step, we verify that if tx failed nonce is not updated
 func (s *TxQueueTestSuite) TestLocalNonce() {
 	txCount := 3
-	key, _ := crypto.GenerateKey()
-	selectedAccount := &account.SelectedExtKey{
-		Address:    account.FromAddress(TestConfig.Account1.Address),
-		AccountKey: &keystore.Key{PrivateKey: key},
-	}
+	selectedAccount := newSelectedAccount()
 	nonce := hexutil.Uint64(0)
 	for i := 0; i < txCount; i++ {
 		tx := Create(context.Background(), SendTxArgs{
@@ -345,3 +1538,21 @@ func (s *TxQueueTestSuite) TestLocalNonce() {
 	resultNonce, _ = s.manager.localNonce.Load(tx.Args.From)
 	s.Equal(uint64(nonce)+1, resultNonce.(uint64))
 }
+
+func (s *TxQueueTestSuite) TestRefreshNonce() {
+	address := account.FromAddress(TestConfig.Account1.Address)
+	s.manager.localNonce.Store(address, uint64(10))
+
+	pending := hexutil.Uint64(7)
+	s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), address, gethrpc.PendingBlockNumber).Return(&pending, nil)
+	s.NoError(s.manager.RefreshNonce(context.Background(), address))
+	nonce, _ := s.manager.localNonce.Load(address)
+	s.Equal(uint64(7), nonce.(uint64))
+
+	// local nonce is kept on error
+	testErr := errors.New("test")
+	s.txServiceMock.EXPECT().GetTransactionCount(gomock.Any(), address, gethrpc.PendingBlockNumber).Return(nil, testErr)
+	s.EqualError(s.manager.RefreshNonce(context.Background(), address), testErr.Error())
+	nonce, _ = s.manager.localNonce.Load(address)
+	s.Equal(uint64(7), nonce.(uint64))
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -12,20 +14,101 @@ import (
 // errors
 var (
 	ErrInvalidSendTxArgs = errors.New("Transaction arguments are invalid (are both 'input' and 'data' fields used?)")
+	ErrInputTooLarge     = errors.New("Transaction input data is too large")
+	ErrMetaTooLarge      = errors.New("Transaction metadata is too large")
+	ErrEmptyContractCode = errors.New("Contract creation transaction has empty code")
 )
 
+// MaxMetaSize is the maximum total size, in bytes, of keys and values of transaction metadata.
+const MaxMetaSize = 1024
+
 // Result is a JSON returned from transaction complete function (used internally)
 type Result struct {
 	Hash  common.Hash
 	Error error
+	// WaitTime is how long transaction stayed in the queue until it was completed or discarded.
+	WaitTime time.Duration
+	// Stage is where completion failed. Empty if transaction was sent or if it failed
+	// before any stage was reached (e.g. transaction is not in the queue).
+	Stage Stage
 }
 
+// Stage is a step of transaction completion.
+type Stage string
+
+const (
+	// StageUnlock is selecting and decrypting the account that signs transaction.
+	StageUnlock Stage = "unlock"
+	// StageEstimate is preparing nonce, gas price and gas of transaction.
+	StageEstimate Stage = "estimate"
+	// StageSign is signing transaction with the selected account.
+	StageSign Stage = "sign"
+	// StageBroadcast is sending signed transaction to the network.
+	StageBroadcast Stage = "broadcast"
+)
+
 // QueuedTx holds enough information to complete the queued transaction.
 type QueuedTx struct {
 	ID      string
 	Context context.Context
 	Args    SendTxArgs
 	Result  chan Result
+	// Warnings are advisory issues found when transaction was queued, see Warning* constants.
+	Warnings []string
+
+	seq          uint64    // order of the transaction in the queue
+	queuedAt     time.Time // when transaction was queued, with monotonic clock reading
+	derivedPrice bool      // gas price is derived from EIP-1559 fees, not chosen by user
+}
+
+// Warnings of queued transactions, sent with transaction.queued signal.
+const (
+	// WarningGasPriceBelowSuggested is reported when gas price is lower than suggested by network.
+	WarningGasPriceBelowSuggested = "gas_price_below_suggested"
+	// WarningRecipientIsContract is reported when value is sent without data to a contract.
+	WarningRecipientIsContract = "recipient_is_contract"
+	// WarningSelfTransaction is reported when transaction is sent to its sender.
+	WarningSelfTransaction = "self_transaction"
+	// WarningDustValue is reported when non-zero value is below the dust threshold of the network.
+	WarningDustValue = "dust_value"
+	// WarningRecipientHasNoCode is reported when contract call is sent to an address without code,
+	// e.g. to a contract which self-destructed. Input is accepted by such address as a plain transfer.
+	WarningRecipientHasNoCode = "recipient_has_no_code"
+	// WarningEIP1559Downgraded is reported when EIP-1559 fees are replaced with gas price
+	// because the chain doesn't support them.
+	WarningEIP1559Downgraded = "eip1559_downgraded"
+)
+
+// NonceStatus describes nonces of an account.
+type NonceStatus struct {
+	Confirmed uint64 `json:"confirmed,omitempty"` // nonce after transactions in the latest block
+	Pending   uint64 `json:"pending"`             // nonce after transactions in the pending pool
+	Local     uint64 `json:"local"`               // nonce of the next transaction sent by this node, if known
+}
+
+// HasGap returns true if the local nonce, which the next transaction of this node
+// is sent with, is ahead of the pending nonce, e.g. nonce 5 is assigned while 4 is
+// missing from the pending pool. New transactions would be stuck behind the gap.
+func (s NonceStatus) HasGap() bool {
+	return s.Local > s.Pending
+}
+
+// SerializedSignRequest is a queued transaction exported for signing
+// outside of the node. It contains no secrets.
+type SerializedSignRequest struct {
+	ID        string     `json:"id"`
+	Args      SendTxArgs `json:"args"`
+	MessageID string     `json:"message_id"`
+}
+
+// NewSerializedSignRequest returns sign request of the queued transaction.
+// Args are copied, so that the request can't be used to modify the transaction.
+func NewSerializedSignRequest(tx *QueuedTx) SerializedSignRequest {
+	return SerializedSignRequest{
+		ID:        tx.ID,
+		Args:      tx.Args.Copy(),
+		MessageID: messageIDFromContext(tx.Context),
+	}
 }
 
 // SendTxArgs represents the arguments to submit a new transaction into the transaction pool.
@@ -38,11 +121,63 @@ type SendTxArgs struct {
 	GasPrice *hexutil.Big    `json:"gasPrice"`
 	Value    *hexutil.Big    `json:"value"`
 	Nonce    *hexutil.Uint64 `json:"nonce"`
+	// EIP-1559 fees are converted to GasPrice when transaction is queued,
+	// as only legacy transactions can be signed.
+	MaxFeePerGas         *hexutil.Big `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *hexutil.Big `json:"maxPriorityFeePerGas,omitempty"`
 	// We keep both "input" and "data" for backward compatibility.
 	// "input" is a preferred field.
 	// see `vendor/github.com/ethereum/go-ethereum/internal/ethapi/api.go:1107`
 	Input hexutil.Bytes `json:"input"`
 	Data  hexutil.Bytes `json:"data"`
+	// Meta is an optional application-defined metadata, e.g. transaction category.
+	// It is echoed in transaction signals and never included in a signed transaction.
+	Meta map[string]string `json:"meta,omitempty"`
+	// Priority defines the order in which pending transactions are presented and
+	// completed in batches, higher first. It doesn't affect the order of transactions
+	// on chain, which depends only on their nonces.
+	Priority int `json:"priority,omitempty"`
+}
+
+// Copy returns a deep copy of args.
+func (args SendTxArgs) Copy() SendTxArgs {
+	cp := args
+	if args.To != nil {
+		to := *args.To
+		cp.To = &to
+	}
+	if args.Gas != nil {
+		gas := *args.Gas
+		cp.Gas = &gas
+	}
+	if args.Nonce != nil {
+		nonce := *args.Nonce
+		cp.Nonce = &nonce
+	}
+	cp.GasPrice = copyBig(args.GasPrice)
+	cp.Value = copyBig(args.Value)
+	cp.MaxFeePerGas = copyBig(args.MaxFeePerGas)
+	cp.MaxPriorityFeePerGas = copyBig(args.MaxPriorityFeePerGas)
+	if args.Input != nil {
+		cp.Input = append(hexutil.Bytes{}, args.Input...)
+	}
+	if args.Data != nil {
+		cp.Data = append(hexutil.Bytes{}, args.Data...)
+	}
+	if args.Meta != nil {
+		cp.Meta = make(map[string]string, len(args.Meta))
+		for k, v := range args.Meta {
+			cp.Meta[k] = v
+		}
+	}
+	return cp
+}
+
+func copyBig(v *hexutil.Big) *hexutil.Big {
+	if v == nil {
+		return nil
+	}
+	return (*hexutil.Big)(new(big.Int).Set(v.ToInt()))
 }
 
 // Valid checks whether this structure is filled in correctly.
@@ -56,6 +191,36 @@ func (args SendTxArgs) Valid() bool {
 	return bytes.Equal(args.Input, args.Data)
 }
 
+// ValidateContractCode checks that contract creation transaction
+// (the one without recipient) has non-empty code.
+func (args SendTxArgs) ValidateContractCode() error {
+	if args.To == nil && isNilOrEmpty(args.GetInput()) {
+		return ErrEmptyContractCode
+	}
+	return nil
+}
+
+// ValidateInputSize checks that input data does not exceed the given limit.
+// Zero limit means that input size is not limited.
+func (args SendTxArgs) ValidateInputSize(limit int) error {
+	if limit > 0 && len(args.GetInput()) > limit {
+		return ErrInputTooLarge
+	}
+	return nil
+}
+
+// ValidateMeta checks that metadata does not exceed MaxMetaSize.
+func (args SendTxArgs) ValidateMeta() error {
+	size := 0
+	for key, value := range args.Meta {
+		size += len(key) + len(value)
+	}
+	if size > MaxMetaSize {
+		return ErrMetaTooLarge
+	}
+	return nil
+}
+
 // GetInput returns either Input or Data field's value dependent on what is filled.
 func (args SendTxArgs) GetInput() hexutil.Bytes {
 	if !isNilOrEmpty(args.Input) {
@@ -1,8 +1,10 @@
 package transactions
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/stretchr/testify/assert"
 )
@@ -31,3 +33,56 @@ func doSendTxValidityTest(t *testing.T, args SendTxArgs, expectValid bool, expec
 		assert.Equal(t, expectValue, args.GetInput(), "GetInput() returned unexpected value")
 	}
 }
+
+func TestSendTxArgsValidateInputSize(t *testing.T) {
+	input := hexutil.Bytes(make([]byte, 10))
+
+	assert.NoError(t, SendTxArgs{Input: input}.ValidateInputSize(0))
+	assert.NoError(t, SendTxArgs{Input: input}.ValidateInputSize(10))
+	assert.Equal(t, ErrInputTooLarge, SendTxArgs{Input: input}.ValidateInputSize(9))
+	assert.Equal(t, ErrInputTooLarge, SendTxArgs{Data: input}.ValidateInputSize(9))
+}
+
+func TestSendTxArgsValidateContractCode(t *testing.T) {
+	to := common.HexToAddress("0x01")
+	assert.NoError(t, SendTxArgs{To: &to}.ValidateContractCode())
+	assert.NoError(t, SendTxArgs{Input: hexutil.Bytes{0x60}}.ValidateContractCode())
+	assert.NoError(t, SendTxArgs{Data: hexutil.Bytes{0x60}}.ValidateContractCode())
+	assert.Equal(t, ErrEmptyContractCode, SendTxArgs{}.ValidateContractCode())
+}
+
+func TestSendTxArgsValidateMeta(t *testing.T) {
+	assert.NoError(t, SendTxArgs{}.ValidateMeta())
+	assert.NoError(t, SendTxArgs{Meta: map[string]string{"category": "swap"}}.ValidateMeta())
+
+	value := string(make([]byte, MaxMetaSize))
+	assert.Equal(t, ErrMetaTooLarge, SendTxArgs{Meta: map[string]string{"category": value}}.ValidateMeta())
+}
+
+func TestSendTxArgsCopy(t *testing.T) {
+	to := common.HexToAddress("0x02")
+	gas := hexutil.Uint64(21000)
+	args := SendTxArgs{
+		To:       &to,
+		Gas:      &gas,
+		GasPrice: (*hexutil.Big)(big.NewInt(10)),
+		Value:    (*hexutil.Big)(big.NewInt(1)),
+		Input:    hexutil.Bytes{0x01},
+		Meta:     map[string]string{"category": "transfer"},
+	}
+	cp := args.Copy()
+	assert.Equal(t, args, cp)
+
+	cp.To[0] = 0xff
+	*cp.Gas = 1
+	cp.GasPrice.ToInt().SetInt64(20)
+	cp.Value.ToInt().SetInt64(2)
+	cp.Input[0] = 0x02
+	cp.Meta["category"] = "swap"
+	assert.Equal(t, common.HexToAddress("0x02"), *args.To)
+	assert.Equal(t, hexutil.Uint64(21000), *args.Gas)
+	assert.Equal(t, int64(10), args.GasPrice.ToInt().Int64())
+	assert.Equal(t, int64(1), args.Value.ToInt().Int64())
+	assert.Equal(t, hexutil.Bytes{0x01}, args.Input)
+	assert.Equal(t, "transfer", args.Meta["category"])
+}
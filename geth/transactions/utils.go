@@ -8,8 +8,11 @@ import (
 	"os"
 	"reflect"
 	"runtime/debug"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/pborman/uuid"
+	"github.com/status-im/status-go/geth/params"
 	"github.com/status-im/status-go/geth/signal"
 )
 
@@ -17,6 +20,14 @@ const (
 	// MessageIDKey is a key for message ID
 	// This ID is required to track from which chat a given send transaction request is coming.
 	MessageIDKey = contextKey("message_id")
+
+	// GasMultiplierKey is a key for a per-send gas multiplier.
+	// When set, it overrides the multiplier from params.TransactionsConfig.
+	GasMultiplierKey = contextKey("gas_multiplier")
+
+	// SkipIfMinedKey is a key for a flag of SendRawTransaction. When true, the transaction
+	// isn't broadcast if its nonce is already used by a mined transaction.
+	SkipIfMinedKey = contextKey("skip_if_mined")
 )
 
 type contextKey string // in order to make sure that our context key does not collide with keys from other packages
@@ -53,6 +64,39 @@ func messageIDFromContext(ctx context.Context) string {
 	return ""
 }
 
+// gasMultiplierFromContext returns gas multiplier from context (if exists)
+func gasMultiplierFromContext(ctx context.Context) (float64, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	multiplier, ok := ctx.Value(GasMultiplierKey).(float64)
+	return multiplier, ok
+}
+
+// skipIfMinedFromContext returns true if SkipIfMinedKey is set
+func skipIfMinedFromContext(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	skip, _ := ctx.Value(SkipIfMinedKey).(bool)
+	return skip
+}
+
+// isPublicNetwork returns true if networkID belongs to a well-known public network.
+func isPublicNetwork(networkID uint64) bool {
+	switch networkID {
+	case params.MainNetworkID, params.RopstenNetworkID, params.RinkebyNetworkID:
+		return true
+	}
+	return false
+}
+
+// isNonceTooLow returns true if err is "nonce too low" error returned by
+// a node. The error comes over RPC, so only its message can be compared.
+func isNonceTooLow(err error) bool {
+	return err != nil && strings.Contains(err.Error(), core.ErrNonceTooLow.Error())
+}
+
 // fatalf is used to halt the execution.
 // When called the function prints stack end exits.
 // Failure is logged into both StdErr and StdOut.
@@ -0,0 +1,72 @@
+package transactions
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/status-im/status-go/geth/params"
+)
+
+// sentValue is value of a transaction sent at a given time.
+type sentValue struct {
+	at    time.Time
+	value *big.Int
+}
+
+// valueTracker keeps values of recently sent transactions.
+type valueTracker struct {
+	mu   sync.Mutex
+	sent []sentValue
+	now  func() time.Time // to be replaced in tests
+}
+
+func newValueTracker() *valueTracker {
+	return &valueTracker{now: time.Now}
+}
+
+// add records value of a sent transaction.
+func (t *valueTracker) add(value *big.Int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sent = append(t.sent, sentValue{at: t.now(), value: value})
+}
+
+// sum returns total value sent within window. Values older than
+// window are forgotten.
+func (t *valueTracker) sum(window time.Duration) *big.Int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	since := t.now().Add(-window)
+	for len(t.sent) > 0 && t.sent[0].at.Before(since) {
+		t.sent = t.sent[1:]
+	}
+	total := new(big.Int)
+	for _, s := range t.sent {
+		total.Add(total, s.value)
+	}
+	return total
+}
+
+// reset forgets all sent values.
+func (t *valueTracker) reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.sent = nil
+}
+
+// exceedsLimit returns true if sending value is over the given limit,
+// taking into account values which are already sent.
+func (t *valueTracker) exceedsLimit(value *big.Int, limit params.ValueLimit) bool {
+	if limit.PerTransaction != nil && value.Cmp(limit.PerTransaction) > 0 {
+		return true
+	}
+	if limit.PerWindow != nil && limit.Window > 0 {
+		total := t.sum(time.Duration(limit.Window) * time.Second)
+		if total.Add(total, value).Cmp(limit.PerWindow) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package transactions
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/status-im/status-go/geth/params"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValueTrackerExceedsLimit(t *testing.T) {
+	tracker := newValueTracker()
+	now := time.Now()
+	tracker.now = func() time.Time { return now }
+
+	limit := params.ValueLimit{
+		PerTransaction: big.NewInt(10),
+		PerWindow:      big.NewInt(15),
+		Window:         60,
+	}
+	require.False(t, tracker.exceedsLimit(big.NewInt(10), limit))
+	require.True(t, tracker.exceedsLimit(big.NewInt(11), limit))
+
+	tracker.add(big.NewInt(10))
+	require.False(t, tracker.exceedsLimit(big.NewInt(5), limit))
+	require.True(t, tracker.exceedsLimit(big.NewInt(6), limit))
+
+	// sent values expire after window
+	now = now.Add(61 * time.Second)
+	require.False(t, tracker.exceedsLimit(big.NewInt(10), limit))
+	require.False(t, tracker.exceedsLimit(big.NewInt(100), params.ValueLimit{}))
+}
@@ -243,6 +243,7 @@ func CompleteTransactions(ids, password *C.char) *C.char {
 			}
 			if result.Error != nil {
 				txResult.Error = result.Error.Error()
+				txResult.Stage = string(result.Stage)
 			}
 			out.Results[txID] = txResult
 		}
@@ -449,6 +450,14 @@ func AddPeer(enode *C.char) *C.char {
 	return makeJSONResponse(err)
 }
 
+// ConfirmChainSwitch approves (approved == 1) or rejects the chain switch
+// reported with chain.switch_requested signal.
+//export ConfirmChainSwitch
+func ConfirmChainSwitch(id *C.char, approved C.int) *C.char {
+	err := statusAPI.ConfirmChainSwitch(C.GoString(id), approved == 1)
+	return makeJSONResponse(err)
+}
+
 // ConnectionChange handles network state changes as reported
 // by ReactNative (see https://facebook.github.io/react-native/docs/netinfo.html)
 //export ConnectionChange
@@ -81,6 +81,7 @@ type CompleteTransactionResult struct {
 	ID    string `json:"id"`
 	Hash  string `json:"hash"`
 	Error string `json:"error"`
+	Stage string `json:"stage,omitempty"`
 }
 
 // CompleteTransactionsResult is list of results from CompleteTransactions() (used in exposed method)
@@ -262,7 +262,7 @@ func (s *TransactionsTestSuite) testSendContractTx(setInputAndDataValue initFunc
 	txHashCheck, err := s.Backend.SendTransaction(context.TODO(), args)
 
 	if expectedError != nil {
-		s.Equal(expectedError, err, expectedErrorDescription)
+		s.Equal(expectedError, transactions.Cause(err), expectedErrorDescription)
 		return
 	}
 	s.NoError(err, "cannot send transaction")
@@ -566,6 +566,63 @@ func (s *TransactionsTestSuite) TestDiscardQueuedTransaction() {
 	s.True(txFailedEventCalled, "expected tx failure signal is not received")
 }
 
+func (s *TransactionsTestSuite) TestLogoutDiscardsQueuedTransactions() {
+	s.StartTestBackend()
+	defer s.StopTestBackend()
+
+	EnsureNodeSync(s.Backend.StatusNode().EnsureSync)
+	s.Backend.TxQueueManager().TransactionQueue().Reset()
+
+	// transactions of the selected account and of an account with unlocked session
+	s.NoError(s.Backend.SelectAccount(TestConfig.Account1.Address, TestConfig.Account1.Password))
+	s.NoError(s.Backend.UnlockSession(TestConfig.Account2.Address, TestConfig.Account2.Password, time.Minute))
+
+	queued := make(chan string, 2)
+	failed := make(chan map[string]interface{}, 2)
+	signal.SetDefaultNodeNotificationHandler(func(jsonEvent string) {
+		var envelope signal.Envelope
+		s.NoError(json.Unmarshal([]byte(jsonEvent), &envelope))
+		switch envelope.Type {
+		case transactions.EventTransactionQueued:
+			queued <- envelope.Event.(map[string]interface{})["id"].(string)
+		case transactions.EventTransactionFailed:
+			failed <- envelope.Event.(map[string]interface{})
+		}
+	})
+
+	senders := []string{TestConfig.Account1.Address, TestConfig.Account2.Address}
+	errs := make(chan error, len(senders))
+	for _, from := range senders {
+		go func(from string) {
+			_, err := s.Backend.SendTransaction(context.TODO(), transactions.SendTxArgs{
+				From:  account.FromAddress(from),
+				To:    account.ToAddress(TestConfig.Account3.Address),
+				Value: (*hexutil.Big)(big.NewInt(1000000000000)),
+			})
+			errs <- err
+		}(from)
+	}
+	for range senders {
+		select {
+		case <-queued:
+		case <-time.After(time.Minute):
+			s.FailNow("transaction is not queued")
+		}
+	}
+
+	s.NoError(s.Backend.Logout())
+	for range senders {
+		select {
+		case event := <-failed:
+			s.Equal(transactions.ErrAccountLoggedOut.Error(), event["error_message"])
+		case <-time.After(time.Minute):
+			s.FailNow("transaction failed signal is not received")
+		}
+		s.EqualError(<-errs, transactions.ErrAccountLoggedOut.Error())
+	}
+	s.Zero(s.Backend.TxQueueManager().TransactionQueue().Count())
+}
+
 func (s *TransactionsTestSuite) TestCompleteMultipleQueuedTransactions() {
 	s.setupLocalNode()
 	defer s.StopTestBackend()